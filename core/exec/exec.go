@@ -4,39 +4,130 @@
 package exec
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"slices"
+	"time"
+
+	"tgp/core/i18n"
+	"tgp/core/plugin"
+	"tgp/core/wasm"
 )
 
+// ErrWaitDelay возвращается Wait, если ввод-вывод команды не завершился за WaitDelay.
+var ErrWaitDelay = exec.ErrWaitDelay
+
 // Cmd представляет команду, готовую к выполнению.
 // API аналогичен os/exec.Cmd, но скрывает WASM сложность.
-// Для не-WASM сборок использует стандартный os/exec.Cmd.
+// Для не-WASM сборок использует стандартный os/exec.Cmd,
+// а при установленном эмуляторе хоста (core/hosttest) - его результаты.
 type Cmd struct {
-	cmd *exec.Cmd
+	// Env - окружение команды в формате "KEY=value". Если nil, используется окружение текущего процесса.
+	// Имена переменных должны быть перечислены в AllowedEnvVars плагина, переданного в core.InitPlugin.
+	Env []string
+	// Stdin - источник stdin команды. Если nil, stdin команды пуст.
+	Stdin io.Reader
+	// Stdout и Stderr - получатели вывода команды. Если nil, вывод отбрасывается (если не используется pipe).
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Process - запущенная команда. Заполняется в Start.
+	Process *Process
+	// Cancel вызывается при отмене контекста команды. Если nil, команда завершается через Process.Kill.
+	Cancel func() error
+	// WaitDelay ограничивает ожидание в Wait после отмены контекста (до завершения команды)
+	// и после завершения команды (до завершения копирования ввода-вывода).
+	// По истечении потоки закрываются. Ноль - без ограничения.
+	WaitDelay time.Duration
+
+	cmd        *exec.Cmd
+	stderrTail *tailBuffer
+	// pty - размер псевдотерминала, заданный UsePTY; ptyMaster - открытый терминал после Start
+	pty       *terminalSize
+	ptyMaster *os.File
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// emulated - результат выполнения команды эмулятором хоста
+	emulated *wasm.CommandResult
+	started  bool
+	// emulatedStdin - данные, записанные в StdinPipe при эмуляции; команда выполняется после закрытия pipe
+	emulatedStdin *bytes.Buffer
+	emulatedErr   error
 }
 
 // Command создает новую команду для выполнения.
+// Команда прерывается при отмене контекста выполнения плагина.
 // name - имя команды (например, "echo")
 // arg - аргументы команды
 func Command(name string, arg ...string) (cmd *Cmd) {
 
-	execCmd := exec.Command(name, arg...)
+	ctx := wasm.Context()
 	return &Cmd{
-		cmd: execCmd,
+		cmd: exec.CommandContext(ctx, name, arg...),
+		ctx: ctx,
 	}
 }
 
 // CommandContext создает новую команду с контекстом для выполнения.
+// Команда прерывается при отмене ctx или контекста выполнения плагина.
 // ctx - контекст для отмены выполнения
 // name - имя команды (например, "echo")
 // arg - аргументы команды
 func CommandContext(ctx context.Context, name string, arg ...string) (cmd *Cmd) {
 
-	execCmd := exec.CommandContext(ctx, name, arg...)
+	ctx, cancel := wasm.WithExecuteContext(ctx)
 	return &Cmd{
-		cmd: execCmd,
+		cmd:    exec.CommandContext(ctx, name, arg...),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// LookPath ищет исполняемый файл name в PATH и возвращает путь к нему.
+// Команда проверяется по AllowedShellCMDs плагина, как в WASM сборках (без зарегистрированного
+// плагина проверка не выполняется). Для не-WASM сборок
+// путь ищется через os/exec.LookPath, а при установленном эмуляторе хоста - запрашивается у эмулятора.
+// Возвращает *Error с ErrNotAllowed или ErrNotFound.
+func LookPath(name string) (path string, err error) {
+
+	if err = checkCommand(name, nil); err != nil {
+		return "", err
+	}
+
+	emulator := wasm.Emulator()
+	if emulator == nil {
+		path, err = exec.LookPath(name)
+		return path, lookPathError(name, err)
+	}
+
+	if path, err = emulator.LookPath(name); err != nil {
+		return "", commandError(name, err)
+	}
+	return path, nil
+}
+
+// Allowed сообщает, разрешена ли команда name в AllowedShellCMDs плагина.
+// Ответ определяется по plugin.Info, переданному в core.InitPlugin (или эмулятору хоста).
+// Без зарегистрированного плагина разрешены все команды.
+func Allowed(name string) (allowed bool) {
+
+	info, checked, err := pluginInfo()
+	return err == nil && (!checked || info.CheckCommand(name) == nil)
+}
+
+// lookPathError заменяет os/exec.ErrNotFound на ErrNotFound.
+func lookPathError(name string, err error) (mapped error) {
+
+	if errors.Is(err, exec.ErrNotFound) {
+		return &Error{Name: name, Err: ErrNotFound}
 	}
+	return err
 }
 
 // Dir устанавливает рабочую директорию для команды.
@@ -49,25 +140,316 @@ func (c *Cmd) Dir(dir string) (cmd *Cmd) {
 // Start запускает команду, но не ждет её завершения.
 func (c *Cmd) Start() (err error) {
 
-	return c.cmd.Start()
+	if err = c.checkPTY(); err != nil {
+		c.release()
+		return err
+	}
+
+	emulator := wasm.Emulator()
+	if emulator == nil {
+		if err = checkCommand(c.cmd.Args[0], c.Env); err != nil {
+			c.release()
+			return err
+		}
+		c.cmd.Env = c.Env
+		if c.Stdin != nil {
+			c.cmd.Stdin = c.Stdin
+		}
+		if c.Stdout != nil {
+			c.cmd.Stdout = c.Stdout
+		}
+		if c.Stderr != nil {
+			c.cmd.Stderr = c.stderrWriter()
+		}
+		if c.Cancel != nil {
+			c.cmd.Cancel = c.Cancel
+		}
+		c.cmd.WaitDelay = c.WaitDelay
+		if c.pty != nil {
+			return c.startPTY()
+		}
+		if err = c.cmd.Start(); err != nil {
+			c.release()
+			return lookPathError(c.cmd.Args[0], err)
+		}
+		c.Process = &Process{process: c.cmd.Process}
+		return nil
+	}
+
+	if c.started {
+		return errors.New(i18n.Msg("command already started"))
+	}
+	if err = context.Cause(c.ctx); err != nil {
+		c.release()
+		return err
+	}
+
+	c.started = true
+	c.Process = &Process{}
+	if c.pty != nil {
+		c.emulatedStdin = &bytes.Buffer{}
+	}
+	// Данные StdinPipe ещё не записаны: команда выполняется при закрытии pipe, чтении вывода или Wait
+	if c.emulatedStdin != nil {
+		return nil
+	}
+	return c.emulate(emulator)
+}
+
+// checkCommand проверяет команду name по AllowedShellCMDs и окружение env по AllowedEnvVars
+// плагина, как это делает хост в WASM сборках.
+func checkCommand(name string, env []string) (err error) {
+
+	var info plugin.Info
+	var checked bool
+	if info, checked, err = pluginInfo(); err != nil || !checked {
+		return err
+	}
+	if err = info.CheckCommand(name); err != nil {
+		return commandError(name, err)
+	}
+	if env != nil {
+		return info.CheckEnv(env)
+	}
+	return nil
+}
+
+// pluginInfo возвращает plugin.Info для проверки команд.
+// checked = false, если не зарегистрированы ни плагин, ни эмулятор хоста (обычная программа
+// или go test без core.InitPlugin): ограничения плагина в этом случае не применяются.
+func pluginInfo() (info plugin.Info, checked bool, err error) {
+
+	if info, err = wasm.PluginInfo(); err != nil {
+		if errors.Is(err, wasm.ErrPluginNotSet) {
+			return info, false, nil
+		}
+		return info, false, err
+	}
+	return info, true, nil
+}
+
+// startPTY запускает команду в псевдотерминале.
+func (c *Cmd) startPTY() (err error) {
+
+	var slave *os.File
+	if c.ptyMaster, slave, err = openPTY(*c.pty); err != nil {
+		c.release()
+		return err
+	}
+	// Копия slave в текущем процессе не нужна после запуска команды
+	defer slave.Close()
+
+	c.cmd.Stdin = slave
+	c.cmd.Stdout = slave
+	c.cmd.Stderr = slave
+	c.cmd.SysProcAttr = ptySysProcAttr()
+	if err = c.cmd.Start(); err != nil {
+		_ = c.ptyMaster.Close()
+		c.release()
+		return lookPathError(c.cmd.Args[0], err)
+	}
+	c.Process = &Process{process: c.cmd.Process}
+	return nil
+}
+
+// Terminal возвращает псевдотерминал команды, запущенной в режиме UsePTY.
+// Должен быть вызван после Start().
+func (c *Cmd) Terminal() (term *Terminal, err error) {
+
+	if c.pty == nil {
+		return nil, errNoPTY
+	}
+
+	// Команда выполняется эмулятором хоста: ввод передаётся ей целиком при закрытии терминала
+	if c.started {
+		stdin := &emulatedStdinPipe{cmd: c}
+		return &Terminal{
+			reader: &emulatedPipe{cmd: c, combined: true},
+			writer: stdin,
+			resize: func(rows uint16, cols uint16) (err error) { return nil },
+			close:  stdin.Close,
+		}, nil
+	}
+
+	if c.ptyMaster == nil {
+		return nil, errors.New(i18n.Msg("command not started: call Start() first"))
+	}
+	master := c.ptyMaster
+	return &Terminal{
+		reader: ptyReader{master: master},
+		writer: master,
+		resize: func(rows uint16, cols uint16) (err error) {
+			return resizePTY(master, terminalSize{rows: rows, cols: cols})
+		},
+		close: master.Close,
+	}, nil
+}
+
+// ptyReader читает вывод терминала; EIO после завершения команды преобразуется в io.EOF.
+type ptyReader struct {
+	master *os.File
+}
+
+// Read читает вывод терминала.
+func (r ptyReader) Read(p []byte) (n int, err error) {
+
+	if n, err = r.master.Read(p); err != nil && isPTYClosed(err) {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// Process представляет запущенную команду (аналог os.Process).
+type Process struct {
+	// process - процесс ОС; nil для команды, выполненной эмулятором хоста
+	process *os.Process
+}
+
+// Signal отправляет сигнал команде.
+// Для завершившейся команды возвращает os.ErrProcessDone.
+func (p *Process) Signal(sig os.Signal) (err error) {
+
+	if p.process == nil {
+		return os.ErrProcessDone
+	}
+	return p.process.Signal(sig)
+}
+
+// Kill немедленно завершает команду.
+func (p *Process) Kill() (err error) {
+
+	return p.Signal(os.Kill)
+}
+
+// emulate выполняет команду эмулятором хоста.
+func (c *Cmd) emulate(emulator wasm.HostEmulator) (err error) {
+
+	defer c.release()
+
+	var stdin []byte
+	switch {
+	case c.emulatedStdin != nil:
+		stdin = c.emulatedStdin.Bytes()
+	case c.Stdin != nil:
+		if stdin, err = io.ReadAll(c.Stdin); err != nil {
+			return fmt.Errorf(i18n.Msg("failed to write stdin")+": %w", err)
+		}
+	}
+
+	workDir := c.cmd.Dir
+	if workDir == "" {
+		workDir = "."
+	}
+
+	var result wasm.CommandResult
+	if result, err = emulator.ExecuteCommand(c.ctx, c.cmd.Args[0], c.cmd.Args[1:], workDir, c.Env, stdin); err != nil {
+		return fmt.Errorf(i18n.Msg("failed to execute command")+": %w", commandError(c.cmd.Args[0], err))
+	}
+
+	c.emulated = &result
+	if c.Stdout != nil {
+		if _, err = c.Stdout.Write(result.Stdout); err != nil {
+			return err
+		}
+	}
+	if c.Stderr != nil {
+		if _, err = c.stderrWriter().Write(result.Stderr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emulatePending выполняет команду, отложенную до закрытия StdinPipe.
+// Повторные вызовы возвращают результат первого.
+func (c *Cmd) emulatePending() (err error) {
+
+	if c.emulated != nil || c.emulatedErr != nil {
+		return c.emulatedErr
+	}
+	if emulator := wasm.Emulator(); emulator != nil {
+		c.emulatedErr = c.emulate(emulator)
+	}
+	return c.emulatedErr
 }
 
 // Wait ждет завершения команды и возвращает ошибку, если команда завершилась с ненулевым кодом выхода.
 func (c *Cmd) Wait() (err error) {
 
-	return c.cmd.Wait()
+	if !c.started {
+		defer c.release()
+		if err = c.cmd.Wait(); err == nil {
+			return nil
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			err = c.exitError(exitErr.ExitCode())
+		}
+		if c.ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", context.Cause(c.ctx), err)
+		}
+		return err
+	}
+
+	if err = c.emulatePending(); err != nil {
+		return err
+	}
+	if c.emulated.ExitCode != 0 {
+		return c.exitError(c.emulated.ExitCode)
+	}
+	return nil
 }
 
 // Run запускает команду и ждет её завершения.
 func (c *Cmd) Run() (err error) {
 
-	return c.cmd.Run()
+	if err = c.Start(); err != nil {
+		return err
+	}
+
+	return c.Wait()
+}
+
+// release освобождает ресурсы контекста, созданного CommandContext.
+func (c *Cmd) release() {
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// StdinPipe возвращает pipe для записи в stdin команды.
+// Должен быть вызван до Start().
+// При эмуляции хоста команда выполняется после закрытия pipe (или при чтении вывода и Wait).
+func (c *Cmd) StdinPipe() (writer io.WriteCloser, err error) {
+
+	if c.Stdin != nil {
+		return nil, errors.New(i18n.Msg("Stdin already set"))
+	}
+
+	if wasm.Emulator() != nil {
+		if c.started {
+			return nil, errors.New(i18n.Msg("StdinPipe after process started"))
+		}
+		c.emulatedStdin = &bytes.Buffer{}
+		return &emulatedStdinPipe{cmd: c}, nil
+	}
+
+	return c.cmd.StdinPipe()
 }
 
 // StdoutPipe возвращает pipe для чтения stdout команды.
 // Должен быть вызван до Start().
 func (c *Cmd) StdoutPipe() (reader io.ReadCloser, err error) {
 
+	if c.Stdout != nil {
+		return nil, errors.New(i18n.Msg("Stdout already set"))
+	}
+
+	if wasm.Emulator() != nil {
+		return &emulatedPipe{cmd: c}, nil
+	}
+
 	return c.cmd.StdoutPipe()
 }
 
@@ -75,6 +457,14 @@ func (c *Cmd) StdoutPipe() (reader io.ReadCloser, err error) {
 // Должен быть вызван до Start().
 func (c *Cmd) StderrPipe() (reader io.ReadCloser, err error) {
 
+	if c.Stderr != nil {
+		return nil, errors.New(i18n.Msg("Stderr already set"))
+	}
+
+	if wasm.Emulator() != nil {
+		return &emulatedPipe{cmd: c, stderr: true}, nil
+	}
+
 	return c.cmd.StderrPipe()
 }
 
@@ -82,9 +472,79 @@ func (c *Cmd) StderrPipe() (reader io.ReadCloser, err error) {
 // Должен быть вызван после Wait().
 func (c *Cmd) ExitCode() (exitCode int) {
 
+	if c.started {
+		if c.emulated == nil {
+			return -1
+		}
+		return c.emulated.ExitCode
+	}
+
 	if c.cmd.ProcessState == nil {
 		return -1
 	}
 
 	return c.cmd.ProcessState.ExitCode()
 }
+
+// emulatedPipe реализует io.ReadCloser для вывода команды, выполненной эмулятором хоста.
+// Вывод становится доступен после Start(), поэтому pipe можно получить как до, так и после запуска.
+type emulatedPipe struct {
+	cmd    *Cmd
+	stderr bool
+	// combined - stdout и stderr вместе (вывод терминала в режиме UsePTY)
+	combined bool
+	reader   *bytes.Reader
+}
+
+// Read читает вывод команды.
+func (p *emulatedPipe) Read(b []byte) (n int, err error) {
+
+	if p.reader == nil {
+		if !p.cmd.started {
+			return 0, errors.New(i18n.Msg("command not started: call Start() first"))
+		}
+		if err = p.cmd.emulatePending(); err != nil {
+			return 0, err
+		}
+		output := p.cmd.emulated.Stdout
+		switch {
+		case p.combined:
+			output = append(slices.Clone(output), p.cmd.emulated.Stderr...)
+		case p.stderr:
+			output = p.cmd.emulated.Stderr
+		}
+		p.reader = bytes.NewReader(output)
+	}
+
+	return p.reader.Read(b)
+}
+
+// Close закрывает pipe.
+func (p *emulatedPipe) Close() (err error) {
+
+	return nil
+}
+
+// emulatedStdinPipe реализует io.WriteCloser для stdin команды, выполняемой эмулятором хоста.
+// Данные накапливаются до закрытия pipe и передаются эмулятору целиком.
+type emulatedStdinPipe struct {
+	cmd *Cmd
+}
+
+// Write записывает данные в stdin команды.
+func (p *emulatedStdinPipe) Write(b []byte) (n int, err error) {
+
+	if p.cmd.emulated != nil || p.cmd.emulatedErr != nil {
+		return 0, io.ErrClosedPipe
+	}
+	return p.cmd.emulatedStdin.Write(b)
+}
+
+// Close закрывает stdin и, если команда запущена, выполняет её.
+func (p *emulatedStdinPipe) Close() (err error) {
+
+	if !p.cmd.started {
+		return nil
+	}
+	return p.cmd.emulatePending()
+}
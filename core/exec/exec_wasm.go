@@ -5,14 +5,22 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"syscall"
 	"time"
 
+	"tgp/core/errs"
 	"tgp/core/i18n"
+	"tgp/core/plugin"
 	"tgp/core/wasm"
 )
 
+// ErrWaitDelay возвращается Wait, если ввод-вывод команды не завершился за WaitDelay.
+var ErrWaitDelay = errors.New(i18n.Msg("exec: WaitDelay expired before I/O complete"))
+
 // Cmd представляет команду, готовую к выполнению.
 // API аналогичен os/exec.Cmd, но скрывает WASM сложность.
 type Cmd struct {
@@ -20,13 +28,72 @@ type Cmd struct {
 	Args    []string
 	WorkDir string
 
+	// Env - окружение команды в формате "KEY=value". Если nil, используется окружение хоста по умолчанию.
+	// Имена переменных должны быть перечислены в AllowedEnvVars.
+	Env []string
+	// Stdin - источник stdin команды. Если nil, stdin команды пуст.
+	// Данные копируются в хост в отдельной горутине; Wait ожидает завершения копирования.
+	Stdin io.Reader
+	// Stdout и Stderr - получатели вывода команды. Если nil, вывод доступен через StdoutPipe/StderrPipe.
+	// Вывод копируется в отдельных горутинах; Wait ожидает завершения копирования.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Process - запущенная команда. Заполняется в Start.
+	Process *Process
+	// Cancel вызывается при отмене контекста команды. Если nil, команда завершается через Process.Kill.
+	Cancel func() error
+	// WaitDelay ограничивает ожидание в Wait после отмены контекста (до завершения команды)
+	// и после завершения команды (до завершения копирования ввода-вывода).
+	// По истечении потоки закрываются. Ноль - без ограничения.
+	WaitDelay time.Duration
+
 	// Внутренние поля для WASM
-	cmdResp *wasm.CommandResponse
-	started bool
-	ctx     context.Context
+	cmdResp       *wasm.CommandResponse
+	started       bool
+	ctx           context.Context
+	cancel        context.CancelFunc
+	stdinPipe     bool
+	stdin         *wasm.StreamWriter
+	copyDone      []chan error
+	stderrTail    *tailBuffer
+	waitCtx       context.Context
+	cancelWait    context.CancelCauseFunc
+	stopInterrupt func() bool
+	// pipeline - этапы конвейера; если задан, команда запускается через host_execute_pipeline
+	pipeline []wasm.CommandRequest
+	// pty - размер псевдотерминала, заданный UsePTY
+	pty *terminalSize
+}
+
+// Process представляет команду, запущенную хостом (аналог os.Process).
+type Process struct {
+	// stdoutStreamID - идентификатор команды в хосте
+	stdoutStreamID uint32
+}
+
+// Signal отправляет сигнал команде через хост.
+// Для завершившейся команды возвращает os.ErrProcessDone.
+func (p *Process) Signal(sig os.Signal) (err error) {
+
+	signal, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf(i18n.Msg("unsupported signal %v")+": %w", sig, errors.ErrUnsupported)
+	}
+	if p.stdoutStreamID == 0 {
+		return os.ErrProcessDone
+	}
+	return wasm.SignalCommand(p.stdoutStreamID, uint32(signal))
+}
+
+// Kill немедленно завершает команду.
+func (p *Process) Kill() (err error) {
+
+	return p.Signal(os.Kill)
 }
 
 // Command создает новую команду для выполнения.
+// Ожидание команды прерывается при отмене контекста выполнения плагина.
 // name - имя команды (например, "echo")
 // arg - аргументы команды
 func Command(name string, arg ...string) (cmd *Cmd) {
@@ -35,21 +102,46 @@ func Command(name string, arg ...string) (cmd *Cmd) {
 		Path:    name,
 		Args:    arg,
 		WorkDir: ".",
-		ctx:     context.Background(),
+		ctx:     wasm.Context(),
+	}
+}
+
+// LookPath ищет исполняемый файл name в PATH хоста и возвращает путь к нему.
+// Команда проверяется по AllowedShellCMDs плагина до обращения к хосту.
+// Возвращает *Error с ErrNotAllowed или ErrNotFound.
+func LookPath(name string) (path string, err error) {
+
+	if err = checkCommand(name, nil); err != nil {
+		return "", err
+	}
+	if path, err = wasm.LookPath(name); err != nil {
+		return "", commandError(name, err)
 	}
+	return path, nil
+}
+
+// Allowed сообщает, разрешена ли команда name в AllowedShellCMDs плагина.
+// Не обращается к хосту: ответ определяется по plugin.Info.
+func Allowed(name string) (allowed bool) {
+
+	info, err := wasm.PluginInfo()
+	return err == nil && info.CheckCommand(name) == nil
 }
 
 // CommandContext создает новую команду с контекстом для выполнения.
+// Ожидание команды прерывается при отмене ctx или контекста выполнения плагина.
 // ctx - контекст для отмены выполнения
 // name - имя команды (например, "echo")
 // arg - аргументы команды
 func CommandContext(ctx context.Context, name string, arg ...string) (cmd *Cmd) {
 
+	ctx, cancel := wasm.WithExecuteContext(ctx)
 	return &Cmd{
 		Path:    name,
 		Args:    arg,
 		WorkDir: ".",
 		ctx:     ctx,
+		cancel:  cancel,
 	}
 }
 
@@ -67,62 +159,267 @@ func (c *Cmd) Start() (err error) {
 		return fmt.Errorf(i18n.Msg("command already started"))
 	}
 
+	if err = context.Cause(c.ctx); err != nil {
+		c.release()
+		return err
+	}
+
+	if err = c.checkPTY(); err != nil {
+		c.release()
+		return err
+	}
+
+	if err = checkCommand(c.Path, c.Env); err != nil {
+		c.release()
+		return err
+	}
+
 	// Выполняем команду через WASM (внутренняя сложность скрыта)
-	cmdResp, cmdErr := wasm.ExecuteCommandInDir(c.Path, c.Args, c.WorkDir)
+	withStdin := c.Stdin != nil || c.stdinPipe || c.pty != nil
+	var cmdResp *wasm.CommandResponse
+	var cmdErr error
+	switch {
+	case c.pty != nil:
+		cmdResp, cmdErr = wasm.ExecuteCommandRequest(wasm.CommandRequest{
+			Command: c.Path,
+			Args:    c.Args,
+			WorkDir: c.WorkDir,
+			Env:     c.Env,
+			Stdin:   true,
+			PTY:     &wasm.TerminalSize{Rows: c.pty.rows, Cols: c.pty.cols},
+		})
+	case c.pipeline != nil:
+		c.pipeline[0].Stdin = withStdin
+		cmdResp, cmdErr = wasm.ExecutePipeline(wasm.PipelineRequest{Stages: c.pipeline})
+	case withStdin || c.Env != nil:
+		cmdResp, cmdErr = wasm.ExecuteCommandRequest(wasm.CommandRequest{
+			Command: c.Path,
+			Args:    c.Args,
+			WorkDir: c.WorkDir,
+			Env:     c.Env,
+			Stdin:   withStdin,
+		})
+	default:
+		cmdResp, cmdErr = wasm.ExecuteCommandInDir(c.Path, c.Args, c.WorkDir)
+	}
+	// Для конвейера хост не сообщает, какой этап не найден, поэтому ошибка не сопоставляется с именем команды
 	if cmdErr != nil {
+		c.release()
+		if c.pipeline == nil {
+			cmdErr = commandError(c.Path, cmdErr)
+		}
 		return fmt.Errorf(i18n.Msg("failed to execute command")+": %w", cmdErr)
 	}
+	// Исполняемый файл не найден хостом: как и в os/exec, ошибка возвращается из Start
+	if cmdResp.Error != "" && cmdResp.Code == errs.NotFound && c.pipeline == nil {
+		c.release()
+		return commandError(c.Path, errs.Decode(cmdResp.Error, cmdResp.Code, cmdResp.Details))
+	}
 
 	c.cmdResp = cmdResp
+	// Без потока stdin ввод не передать: хост уже запустил команду, поэтому она завершается до возврата ошибки
+	if withStdin && cmdResp.Error == "" && cmdResp.StdinStreamID == 0 {
+		c.killStarted()
+		c.release()
+		return errors.New(i18n.Msg("stdin stream not available"))
+	}
 	c.started = true
 
+	if cmdResp.Error != "" {
+		return nil
+	}
+
+	// streamID всегда положительный, безопасное преобразование int32 -> uint32
+	c.Process = &Process{stdoutStreamID: uint32(max(cmdResp.StdoutStreamID, 0))} //nolint:gosec // streamID >= 0
+	c.waitCtx, c.cancelWait = context.WithCancelCause(context.Background())
+	c.stopInterrupt = context.AfterFunc(c.ctx, c.interrupt)
+
+	if c.Stdout != nil && cmdResp.StdoutStreamID > 0 {
+		// streamID всегда положительный, безопасное преобразование int32 -> uint32
+		c.copyOutput(c.Stdout, uint32(cmdResp.StdoutStreamID)) //nolint:gosec // streamID всегда > 0
+	}
+	if c.Stderr != nil && cmdResp.StderrStreamID > 0 {
+		// streamID всегда положительный, безопасное преобразование int32 -> uint32
+		c.copyOutput(c.stderrWriter(), uint32(cmdResp.StderrStreamID)) //nolint:gosec // streamID всегда > 0
+	}
+
+	if withStdin {
+		// streamID всегда положительный, безопасное преобразование int32 -> uint32
+		c.stdin = wasm.NewStreamWriter(uint32(cmdResp.StdinStreamID)) //nolint:gosec // streamID всегда > 0
+		if c.Stdin != nil {
+			done := make(chan error, 1)
+			c.copyDone = append(c.copyDone, done)
+			go c.copyStdin(done)
+		}
+	}
+
+	return nil
+}
+
+// killStarted завершает команду, запущенную хостом до ошибки в Start, ожидает её завершения и закрывает потоки.
+func (c *Cmd) killStarted() {
+
+	if c.cmdResp.StdoutStreamID > 0 {
+		// streamID всегда положительный, безопасное преобразование int32 -> uint32
+		stdoutStreamID := uint32(c.cmdResp.StdoutStreamID) //nolint:gosec // streamID всегда > 0
+		_ = wasm.SignalCommand(stdoutStreamID, uint32(syscall.SIGKILL))
+		_, _ = wasm.WaitCommandResponse(context.Background(), stdoutStreamID)
+	}
+	c.closeStreams()
+}
+
+// copyOutput копирует поток вывода streamID в writer в отдельной горутине.
+func (c *Cmd) copyOutput(writer io.Writer, streamID uint32) {
+
+	done := make(chan error, 1)
+	c.copyDone = append(c.copyDone, done)
+	go func() {
+		if _, err := io.Copy(writer, wasm.NewStreamReader(streamID)); err != nil {
+			done <- fmt.Errorf(i18n.Msg("failed to read command output")+": %w", err)
+			return
+		}
+		done <- nil
+	}()
+}
+
+// interrupt останавливает команду при отмене контекста.
+// Если остановить команду не удалось, ожидание в Wait прерывается сразу,
+// иначе - через WaitDelay (если задан).
+func (c *Cmd) interrupt() {
+
+	cancel := c.Cancel
+	if cancel == nil {
+		cancel = c.Process.Kill
+	}
+	if err := cancel(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		c.cancelWait(err)
+		return
+	}
+	if c.WaitDelay > 0 {
+		time.AfterFunc(c.WaitDelay, func() { c.cancelWait(ErrWaitDelay) })
+	}
+}
+
+// closeStreams закрывает потоки команды в хосте, прерывая копирование ввода-вывода.
+func (c *Cmd) closeStreams() {
+
+	for _, streamID := range []int32{c.cmdResp.StdoutStreamID, c.cmdResp.StderrStreamID} {
+		if streamID > 0 {
+			_ = wasm.CloseStream(uint32(streamID)) //nolint:gosec // streamID всегда > 0
+		}
+	}
+	if c.stdin != nil {
+		_ = c.stdin.Close()
+	}
+}
+
+// waitCopy ожидает завершения горутин копирования ввода-вывода.
+// Если задан WaitDelay, ожидание ограничено им: затем потоки закрываются и возвращается ErrWaitDelay.
+func (c *Cmd) waitCopy() (err error) {
+
+	var expired <-chan time.Time
+	if c.WaitDelay > 0 {
+		timer := time.NewTimer(c.WaitDelay)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for _, done := range c.copyDone {
+		select {
+		case copyErr := <-done:
+			if copyErr != nil && err == nil {
+				err = copyErr
+			}
+		case <-expired:
+			c.closeStreams()
+			return ErrWaitDelay
+		}
+	}
+	return err
+}
+
+// checkCommand проверяет команду name по AllowedShellCMDs и окружение env по AllowedEnvVars
+// плагина до обращения к хосту.
+func checkCommand(name string, env []string) (err error) {
+
+	var info plugin.Info
+	if info, err = wasm.PluginInfo(); err != nil {
+		return err
+	}
+	if err = info.CheckCommand(name); err != nil {
+		return commandError(name, err)
+	}
+	if env != nil {
+		return info.CheckEnv(env)
+	}
 	return nil
 }
 
+// copyStdin копирует Stdin в поток stdin команды и закрывает его.
+func (c *Cmd) copyStdin(done chan<- error) {
+
+	_, err := io.Copy(c.stdin, c.Stdin)
+	if closeErr := c.stdin.Close(); err == nil {
+		err = closeErr
+	}
+	// Команда может завершиться, не дочитав stdin: как и в os/exec, это не ошибка
+	if err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		done <- fmt.Errorf(i18n.Msg("failed to write stdin")+": %w", err)
+		return
+	}
+	done <- nil
+}
+
 // Wait ждет завершения команды и возвращает ошибку, если команда завершилась с ненулевым кодом выхода.
 func (c *Cmd) Wait() (err error) {
 
 	if !c.started {
 		return fmt.Errorf(i18n.Msg("command not started"))
 	}
+	defer c.release()
 
 	if c.cmdResp == nil {
 		return fmt.Errorf(i18n.Msg("command response is nil"))
 	}
 
 	if c.cmdResp.Error != "" {
-		return fmt.Errorf(i18n.Msg("command failed: %s"), c.cmdResp.Error)
+		return fmt.Errorf(i18n.Msg("command failed")+": %w", errs.Decode(c.cmdResp.Error, c.cmdResp.Code, c.cmdResp.Details))
 	}
+	defer c.stopInterrupt()
 
-	// Пытаемся получить обновленный CommandResponse из хоста с несколькими попытками
-	// Команда выполняется асинхронно, поэтому exitCode может быть доступен не сразу
-	// Начальное значение exitCode = -2 означает "команда еще не завершена"
+	// Команда выполняется асинхронно: ожидаем её завершения через хост.
+	// При отмене контекста команда останавливается (interrupt), а ожидание продолжается до её завершения
 	if c.cmdResp.StdoutStreamID > 0 {
 		// streamID всегда положительный, безопасное преобразование int32 -> uint32
 		stdoutStreamID := uint32(c.cmdResp.StdoutStreamID) //nolint:gosec // streamID всегда > 0
-		maxRetries := 100                                  // Максимум 100 попыток
-		for i := 0; i < maxRetries; i++ {
-			if updatedResp, err := wasm.GetCommandResponse(stdoutStreamID); err == nil {
-				// Обновляем exitCode в cmdResp для последующего использования
-				c.cmdResp.ExitCode = updatedResp.ExitCode
-				// Если exitCode != -2, команда завершилась (может быть 0 для успеха или другой код)
-				// -2 - специальное значение для "команда еще не завершена"
-				if updatedResp.ExitCode != -2 {
-					break
-				}
-			}
-			// Ждем немного перед следующей попыткой (кроме первой)
-			if i > 0 {
-				time.Sleep(time.Millisecond * 5)
+		updatedResp, waitErr := wasm.WaitCommandResponse(c.waitCtx, stdoutStreamID)
+		if waitErr != nil {
+			c.closeStreams()
+			if c.ctx.Err() != nil {
+				return fmt.Errorf(i18n.Msg("command wait canceled")+": %w: %w", context.Cause(c.ctx), waitErr)
 			}
+			return fmt.Errorf(i18n.Msg("failed to get command response")+": %w", waitErr)
 		}
+		c.cmdResp.ExitCode = updatedResp.ExitCode
+		c.cmdResp.ExitCodes = updatedResp.ExitCodes
+	}
+
+	// Как и в os/exec, pipe stdin закрывается после завершения команды
+	if (c.stdinPipe || c.pty != nil) && c.stdin != nil {
+		_ = c.stdin.Close()
 	}
 
+	// Дожидаемся копирования ввода-вывода: после завершения команды хост закрывает потоки
+	copyErr := c.waitCopy()
+
 	if c.cmdResp.ExitCode != 0 {
-		return fmt.Errorf(i18n.Msg("command exited with code %d"), c.cmdResp.ExitCode)
+		if c.ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", context.Cause(c.ctx), c.exitError(c.cmdResp.ExitCode))
+		}
+		return c.exitError(c.cmdResp.ExitCode)
 	}
 
-	return nil
+	return copyErr
 }
 
 // Run запускает команду и ждет её завершения.
@@ -135,6 +432,83 @@ func (c *Cmd) Run() (err error) {
 	return c.Wait()
 }
 
+// release освобождает ресурсы контекста, созданного CommandContext.
+func (c *Cmd) release() {
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// StdinPipe возвращает pipe для записи в stdin команды.
+// Должен быть вызван до Start(); запись доступна после Start().
+// Закрытие pipe передаёт команде EOF; если pipe не закрыт, Wait закрывает его после завершения команды.
+func (c *Cmd) StdinPipe() (writer io.WriteCloser, err error) {
+
+	if c.Stdin != nil {
+		return nil, errors.New(i18n.Msg("Stdin already set"))
+	}
+	if c.started {
+		return nil, errors.New(i18n.Msg("StdinPipe after process started"))
+	}
+
+	c.stdinPipe = true
+	return &stdinPipe{cmd: c}, nil
+}
+
+// stdinPipe реализует io.WriteCloser для stdin команды.
+// Поток stdin создаётся хостом при Start(), поэтому pipe ссылается на команду.
+type stdinPipe struct {
+	cmd *Cmd
+}
+
+// Write записывает данные в stdin команды.
+func (p *stdinPipe) Write(b []byte) (n int, err error) {
+
+	if p.cmd.stdin == nil {
+		return 0, errors.New(i18n.Msg("command not started: call Start() first"))
+	}
+	return p.cmd.stdin.Write(b)
+}
+
+// Close закрывает stdin команды.
+func (p *stdinPipe) Close() (err error) {
+
+	if p.cmd.stdin == nil {
+		return nil
+	}
+	return p.cmd.stdin.Close()
+}
+
+// Terminal возвращает псевдотерминал команды, запущенной в режиме UsePTY.
+// Должен быть вызван после Start().
+func (c *Cmd) Terminal() (term *Terminal, err error) {
+
+	if c.pty == nil {
+		return nil, errNoPTY
+	}
+	if c.cmdResp == nil || c.stdin == nil {
+		return nil, errors.New(i18n.Msg("command not started: call Start() first"))
+	}
+	if c.cmdResp.StdoutStreamID <= 0 {
+		return nil, errors.New(i18n.Msg("stdout stream not available"))
+	}
+
+	// streamID всегда положительный, безопасное преобразование int32 -> uint32
+	stdoutStreamID := uint32(c.cmdResp.StdoutStreamID) //nolint:gosec // streamID всегда > 0
+	reader := wasm.NewStreamReader(stdoutStreamID)
+	return &Terminal{
+		reader: reader,
+		writer: c.stdin,
+		resize: func(rows uint16, cols uint16) (err error) {
+			return wasm.ResizeTerminal(stdoutStreamID, rows, cols)
+		},
+		close: func() (err error) {
+			return errors.Join(c.stdin.Close(), reader.Close())
+		},
+	}, nil
+}
+
 // StdoutPipe возвращает pipe для чтения stdout команды.
 // Должен быть вызван до Start().
 // После вызова Start() команда уже выполнена, поэтому pipe доступен сразу.
@@ -143,6 +517,10 @@ func (c *Cmd) StdoutPipe() (reader io.ReadCloser, err error) {
 	// НЕ запускаем команду здесь - это должно быть сделано явно через Start()
 	// StdoutPipe только возвращает reader, команда должна быть запущена отдельно
 
+	if c.Stdout != nil {
+		return nil, errors.New(i18n.Msg("Stdout already set"))
+	}
+
 	if c.cmdResp == nil {
 		return nil, fmt.Errorf(i18n.Msg("command not started: call Start() first"))
 	}
@@ -165,6 +543,10 @@ func (c *Cmd) StderrPipe() (reader io.ReadCloser, err error) {
 	// НЕ запускаем команду здесь - это должно быть сделано явно через Start()
 	// StderrPipe только возвращает reader, команда должна быть запущена отдельно
 
+	if c.Stderr != nil {
+		return nil, errors.New(i18n.Msg("Stderr already set"))
+	}
+
 	if c.cmdResp == nil {
 		return nil, fmt.Errorf(i18n.Msg("command not started: call Start() first"))
 	}
@@ -0,0 +1,77 @@
+package gen
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		old       string
+		new       string
+		oldExists bool
+		newExists bool
+		want      string
+	}{
+		{
+			name:      "created",
+			new:       "a\nb\n",
+			newExists: true,
+			want:      "--- /dev/null\n+++ b/f.txt\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:      "deleted",
+			old:       "a\n",
+			oldExists: true,
+			want:      "--- a/f.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:      "modified",
+			old:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:       "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n",
+			oldExists: true,
+			newExists: true,
+			want:      "--- a/f.txt\n+++ b/f.txt\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:      "modified in separate hunks",
+			old:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:       "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			oldExists: true,
+			newExists: true,
+			want:      "--- a/f.txt\n+++ b/f.txt\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:      "no trailing newline",
+			old:       "a\nb",
+			new:       "a\nc",
+			oldExists: true,
+			newExists: true,
+			want:      "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			name:      "trailing newline added",
+			old:       "a",
+			new:       "a\n",
+			oldExists: true,
+			newExists: true,
+			want:      "--- a/f.txt\n+++ b/f.txt\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		},
+		{
+			name:      "unchanged",
+			old:       "a\n",
+			new:       "a\n",
+			oldExists: true,
+			newExists: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := unifiedDiff("f.txt", []byte(tt.old), []byte(tt.new), tt.oldExists, tt.newExists); got != tt.want {
+				t.Errorf("unifiedDiff:\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
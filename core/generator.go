@@ -2,20 +2,81 @@
 package core
 
 import (
+	"tgp/core/errs"
+	"tgp/core/gen"
+	"tgp/core/i18n"
 	"tgp/core/wasm"
 )
 
+// ErrOverlayUnsupported возвращается в режимах dry-run и check, если генератор не реализует OverlayGenerator.
+// Совместима с errors.Is(err, errors.ErrUnsupported).
+var ErrOverlayUnsupported = errs.New(errs.Unimplemented, i18n.Msg("generator does not support dry-run and check"))
+
 // InitGenerator определяет интерфейс для генерации кода при plugin init.
+// Файлы рекомендуется записывать через сеанс core/gen: тогда Cleanup удаляет только то, что записал Generate.
 type InitGenerator interface {
 	Generate(rootDir string, moduleName string) (err error)
 	Cleanup(rootDir string) (err error)
 }
 
+// OverlayGenerator определяет необязательный интерфейс генератора с поддержкой режимов dry-run и check.
+// Генератор подтверждает, что записывает файлы только через gen.CurrentFS() или сеанс core/gen:
+// тогда записи собираются в памяти и возвращаются хосту в виде diff. Запись через os.WriteFile
+// перехватить нельзя, поэтому для остальных генераторов dry-run и check возвращают ErrOverlayUnsupported.
+type OverlayGenerator interface {
+	InitGenerator
+	SupportsOverlay() (supported bool)
+}
+
 // SetInitGeneratorInstance устанавливает экземпляр генератора.
 func SetInitGeneratorInstance(g InitGenerator) {
 
 	wasm.SetInitGeneratorInstance(
-		g.Generate,
+		func(rootDir string, moduleName string, options wasm.GenerateOptions) (report *wasm.GenerateReport, err error) {
+			return generate(g, rootDir, moduleName, options)
+		},
 		g.Cleanup,
 	)
 }
+
+// generate выполняет генерацию; в режимах DryRun и Check - через gen.Overlay, не изменяя диск.
+// Режимы DryRun и Check доступны только генераторам, реализующим OverlayGenerator.
+func generate(g InitGenerator, rootDir string, moduleName string, options wasm.GenerateOptions) (report *wasm.GenerateReport, err error) {
+
+	run := func() (err error) {
+		return g.Generate(rootDir, moduleName)
+	}
+
+	if options.Check || options.DryRun {
+		if overlay, ok := g.(OverlayGenerator); !ok || !overlay.SupportsOverlay() {
+			return nil, ErrOverlayUnsupported
+		}
+	}
+
+	var changes []gen.Change
+	switch {
+	case options.Check:
+		changes, err = gen.Check(rootDir, run)
+	case options.DryRun:
+		changes, err = gen.Preview(rootDir, run)
+	default:
+		return nil, run()
+	}
+	if changes == nil && err != nil {
+		return nil, err
+	}
+
+	report = &wasm.GenerateReport{Files: make([]wasm.GeneratedFile, 0, len(changes))}
+	for _, change := range changes {
+		report.Files = append(report.Files, wasm.GeneratedFile{Path: change.Path, Status: string(change.Kind), Diff: change.Diff})
+		switch change.Kind {
+		case gen.ChangeCreated:
+			report.Summary.Created++
+		case gen.ChangeModified:
+			report.Summary.Modified++
+		case gen.ChangeDeleted:
+			report.Summary.Deleted++
+		}
+	}
+	return report, err
+}
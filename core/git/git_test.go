@@ -0,0 +1,178 @@
+//go:build !wasip1
+
+package git_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"tgp/core/data"
+	"tgp/core/exec"
+	"tgp/core/git"
+	"tgp/core/hosttest"
+	"tgp/core/plugin"
+)
+
+// gitPlugin - плагин, которому разрешён только git.
+type gitPlugin struct{}
+
+func (gitPlugin) Info() (info plugin.Info, err error) {
+
+	return plugin.Info{Name: "git-test", AllowedShellCMDs: []string{"git"}}, nil
+}
+
+func (gitPlugin) Execute(string, data.Storage, ...string) (response data.Storage, err error) {
+
+	return data.NewStorage(), nil
+}
+
+// newRepo создаёт репозиторий с двумя коммитами и тегами, изменённым, переименованным и неотслеживаемым файлом.
+func newRepo(t *testing.T) (dir string) {
+
+	t.Helper()
+	if _, err := osexec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir = t.TempDir()
+	gitCmd := func(args ...string) string {
+		t.Helper()
+		cmd := osexec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "tag.gpgSign=false", "-c", "commit.gpgSign=false"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	writeFile := func(name string, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitCmd("init", "-q", "-b", "main")
+	writeFile("a.txt", "one\n")
+	gitCmd("add", "a.txt")
+	gitCmd("commit", "-q", "-m", "initial")
+	gitCmd("tag", "v1.0.0")
+
+	writeFile("a.txt", "one\ntwo\n")
+	writeFile("b.txt", "bee\n")
+	gitCmd("add", "a.txt", "b.txt")
+	gitCmd("commit", "-q", "-m", "second", "-m", "details")
+	gitCmd("tag", "v1.9.0")
+	gitCmd("tag", "-a", "v1.10.0", "-m", "release")
+
+	writeFile("a.txt", "one\ntwo\nthree\n")
+	gitCmd("mv", "b.txt", "c.txt")
+	writeFile("u.txt", "untracked\n")
+	return dir
+}
+
+func TestRealRepository(t *testing.T) {
+
+	dir := newRepo(t)
+
+	h, err := hosttest.New(gitPlugin{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	h.PassthroughCommand("git")
+
+	ctx := context.Background()
+
+	commits, err := git.Log(ctx, dir)
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Log: got %d commits, want 2", len(commits))
+	}
+	head, initial := commits[0], commits[1]
+	if head.Subject != "second" || head.Body != "details" || initial.Subject != "initial" {
+		t.Errorf("Log: unexpected subjects %q/%q, body %q", head.Subject, initial.Subject, head.Body)
+	}
+	if !slices.Equal(head.Parents, []string{initial.Hash}) || len(initial.Parents) != 0 {
+		t.Errorf("Log: unexpected parents %v, %v", head.Parents, initial.Parents)
+	}
+	if head.Author.Name != "Test" || head.Author.Email != "test@example.com" || head.Author.When.IsZero() {
+		t.Errorf("Log: unexpected author %+v", head.Author)
+	}
+
+	status, err := git.Status(ctx, dir)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Branch.Head != "main" || status.Branch.Commit != head.Hash {
+		t.Errorf("Status: unexpected branch %+v", status.Branch)
+	}
+	wantEntries := []git.StatusEntry{
+		{Kind: git.EntryChanged, Staged: '.', Unstaged: 'M', Path: "a.txt"},
+		{Kind: git.EntryRenamed, Staged: 'R', Unstaged: '.', Path: "c.txt", OrigPath: "b.txt", Score: "R100"},
+		{Kind: git.EntryUntracked, Staged: '?', Unstaged: '?', Path: "u.txt"},
+	}
+	if !slices.Equal(status.Entries, wantEntries) {
+		t.Errorf("Status: got %+v, want %+v", status.Entries, wantEntries)
+	}
+
+	patch, err := git.Diff(ctx, dir, initial.Hash, head.Hash)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	wantFiles := []git.FileStat{{Path: "a.txt", Added: 1}, {Path: "b.txt", Added: 1}}
+	if !slices.Equal(patch.Files, wantFiles) {
+		t.Errorf("Diff: got %+v, want %+v", patch.Files, wantFiles)
+	}
+	if !strings.Contains(patch.Text, "+two") {
+		t.Errorf("Diff: patch text does not contain the added line:\n%s", patch.Text)
+	}
+
+	staged, err := git.Diff(ctx, dir, "--cached")
+	if err != nil {
+		t.Fatalf("Diff --cached: %v", err)
+	}
+	wantStaged := []git.FileStat{{Path: "c.txt", OldPath: "b.txt"}}
+	if !slices.Equal(staged.Files, wantStaged) {
+		t.Errorf("Diff --cached: got %+v, want %+v", staged.Files, wantStaged)
+	}
+
+	tags, err := git.Tags(ctx, dir)
+	if err != nil {
+		t.Fatalf("Tags: %v", err)
+	}
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	if !slices.Equal(names, []string{"v1.10.0", "v1.9.0", "v1.0.0"}) {
+		t.Fatalf("Tags: got %v", names)
+	}
+	if !tags[0].Annotated || tags[0].Commit != head.Hash || tags[0].Subject != "release" {
+		t.Errorf("Tags: unexpected annotated tag %+v", tags[0])
+	}
+	if tags[1].Annotated || tags[1].Commit != head.Hash || tags[2].Commit != initial.Hash {
+		t.Errorf("Tags: unexpected lightweight tags %+v, %+v", tags[1], tags[2])
+	}
+}
+
+func TestNotScriptedCommand(t *testing.T) {
+
+	h, err := hosttest.New(gitPlugin{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if _, err = git.Status(context.Background(), t.TempDir()); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("Status without PassthroughCommand: got %v, want exec.ErrNotFound", err)
+	}
+}
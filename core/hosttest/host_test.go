@@ -0,0 +1,182 @@
+//go:build !wasip1
+
+package hosttest_test
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"tgp/core/data"
+	"tgp/core/hosttest"
+	"tgp/core/plugin"
+	"tgp/core/wasm"
+)
+
+// testPlugin - плагин с заданным plugin.Info.
+type testPlugin struct {
+	info plugin.Info
+}
+
+func (p testPlugin) Info() (info plugin.Info, err error) {
+
+	return p.info, nil
+}
+
+func (testPlugin) Execute(string, data.Storage, ...string) (response data.Storage, err error) {
+
+	return data.NewStorage(), nil
+}
+
+func newHost(t *testing.T, info plugin.Info) (h *hosttest.Host) {
+
+	t.Helper()
+	h, err := hosttest.New(testPlugin{info: info})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	return h
+}
+
+func TestLogs(t *testing.T) {
+
+	h := newHost(t, plugin.Info{Name: "log-test"})
+
+	logger := slog.Default().With("a", 1).WithGroup("g").With("b", 2).WithGroup("h")
+	logger.Warn("grouped", "c", 3, slog.Group("inner", "d", 4, slog.Group("", "e", 5)), "err", errors.New("boom"))
+	slog.Info("plain", slog.Group("group", "x", "y"))
+
+	records := h.Logs()
+	if len(records) != 2 {
+		t.Fatalf("Logs: got %d records, want 2", len(records))
+	}
+	wantGrouped := map[string]any{"a": int64(1), "g.b": int64(2), "g.h.c": int64(3), "g.h.inner.d": int64(4), "g.h.inner.e": int64(5), "g.h.err": "boom"}
+	if records[0].Level != slog.LevelWarn || !maps.Equal(records[0].Attrs, wantGrouped) {
+		t.Errorf("grouped record: got %v %+v, want %+v", records[0].Level, records[0].Attrs, wantGrouped)
+	}
+	wantPlain := map[string]any{"group.x": "y"}
+	if !maps.Equal(records[1].Attrs, wantPlain) {
+		t.Errorf("plain record: got %+v, want %+v", records[1].Attrs, wantPlain)
+	}
+
+	if !h.HasLog(slog.LevelWarn, "group") || h.HasLog(slog.LevelError, "group") {
+		t.Error("HasLog: unexpected result")
+	}
+}
+
+func TestClock(t *testing.T) {
+
+	h := newHost(t, plugin.Info{Name: "clock-test"})
+	clock := h.Clock()
+	start := clock.Now()
+
+	var order []string
+	fast, err := wasm.StartTask(time.Second, func() bool {
+		order = append(order, "fast")
+		return len(order) < 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, err := wasm.StartTask(2*time.Second, func() bool {
+		order = append(order, "slow")
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if executed := clock.Advance(500 * time.Millisecond); executed != 0 {
+		t.Fatalf("Advance before the first interval: executed %d", executed)
+	}
+	if executed := clock.Advance(2 * time.Second); executed != 3 {
+		t.Fatalf("Advance: executed %d, want 3", executed)
+	}
+	if want := []string{"fast", "fast", "slow"}; !slices.Equal(order, want) {
+		t.Errorf("order: got %v, want %v", order, want)
+	}
+	if got := clock.Now().Sub(start); got != 2500*time.Millisecond {
+		t.Errorf("Now: advanced by %s, want 2.5s", got)
+	}
+
+	clock.Advance(time.Second)
+	if clock.Executions(fast) != 3 {
+		t.Errorf("Executions(fast): got %d, want 3", clock.Executions(fast))
+	}
+	if got := clock.ActiveTasks(); !slices.Equal(got, []uint32{slow}) {
+		t.Errorf("ActiveTasks: got %v, want [%d]", got, slow)
+	}
+
+	if err = wasm.StopTask(slow); err != nil {
+		t.Fatal(err)
+	}
+	if err = wasm.StopTask(slow); err == nil {
+		t.Error("StopTask of a stopped task: want error")
+	}
+	if executed := clock.Advance(time.Minute); executed != 0 {
+		t.Errorf("Advance after StopTask: executed %d", executed)
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+
+	base := t.TempDir()
+	allowed := filepath.Join(base, "allowed")
+	readOnly := filepath.Join(base, "readonly")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{allowed, readOnly, outside} {
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Ссылка внутри разрешённого каталога ведёт за его пределы, а ссылка снаружи - внутрь
+	if err := os.Symlink(outside, filepath.Join(allowed, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(allowed, filepath.Join(outside, "inside")); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newHost(t, plugin.Info{
+		Name: "path-test",
+		AllowedPaths: map[string]string{
+			allowed:      plugin.AccessWrite,
+			"@ro/nested": plugin.AccessRead,
+		},
+	})
+	h.MapPrefix("@ro", readOnly)
+
+	tests := []struct {
+		name    string
+		path    string
+		write   bool
+		allowed bool
+	}{
+		{"write inside", filepath.Join(allowed, "new", "file.txt"), true, true},
+		{"read prefix", filepath.Join(readOnly, "nested", "file.txt"), false, true},
+		{"write read-only prefix", filepath.Join(readOnly, "nested", "file.txt"), true, false},
+		{"read prefix parent", filepath.Join(readOnly, "file.txt"), false, false},
+		{"outside", filepath.Join(outside, "file.txt"), false, false},
+		{"symlink escape", filepath.Join(allowed, "escape", "file.txt"), false, false},
+		{"symlink into allowed", filepath.Join(outside, "inside", "file.txt"), true, true},
+		{"dot-dot escape", filepath.Join(allowed, "..", "outside", "file.txt"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := h.CheckPath(tt.path, tt.write)
+			if tt.allowed && err != nil {
+				t.Errorf("CheckPath(%q, %v): unexpected error %v", tt.path, tt.write, err)
+			}
+			if !tt.allowed && !errors.Is(err, os.ErrPermission) {
+				t.Errorf("CheckPath(%q, %v): got %v, want os.ErrPermission", tt.path, tt.write, err)
+			}
+		})
+	}
+}
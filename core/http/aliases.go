@@ -63,9 +63,6 @@ type RoundTripper = nethttp.RoundTripper
 
 // Функции
 
-// ListenAndServeTLS запускает HTTPS сервер на указанном адресе.
-var ListenAndServeTLS = nethttp.ListenAndServeTLS
-
 // Serve запускает HTTP сервер на указанном listener.
 var Serve = nethttp.Serve
 
@@ -4,26 +4,24 @@
 package http
 
 import (
-	"context"
-	"net"
 	"net/http"
 )
 
-// NewTransport создаёт новый http.Transport, использующий стандартный net.Dial.
+// NewTransport создаёт новый http.Transport, использующий net.Dial из core/net.
+// Подключения проверяются по AllowedHosts так же, как в WASM сборке; до core.InitPlugin
+// (или установки эмулятора хоста) проверка не выполняется.
+// TLS соединения устанавливаются с TLSClientConfig транспорта, если он задан.
 func NewTransport() (transport *http.Transport) {
 
-	return &http.Transport{
-		DialContext: func(ctx context.Context, network, addr string) (conn net.Conn, err error) {
-			dialer := &net.Dialer{}
-			return dialer.DialContext(ctx, network, addr)
-		},
-	}
+	return newTransport()
 }
 
-// NewClient создаёт новый http.Client с Transport, использующим стандартный net.Dial.
+// NewClient создаёт новый http.Client с Transport, использующим net.Dial из core/net.
+// Запросы клиента прерываются при отмене контекста выполнения плагина.
+// Хосты проверяются по AllowedHosts плагина после core.InitPlugin (см. NewTransport).
 func NewClient() (client *http.Client) {
 
 	return &http.Client{
-		Transport: NewTransport(),
+		Transport: &contextTransport{base: NewTransport()},
 	}
 }
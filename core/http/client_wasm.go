@@ -5,24 +5,21 @@ package http
 
 import (
 	"net/http"
-
-	"tgp/core/net"
 )
 
 // NewTransport создаёт новый http.Transport, использующий net.Dial из core/net.
+// TLS соединения устанавливаются с TLSClientConfig транспорта, если он задан.
 func NewTransport() (transport *http.Transport) {
 
-	return &http.Transport{
-		DialContext:    net.DialContext,
-		DialTLSContext: net.DialTLSContext,
-	}
+	return newTransport()
 }
 
 // NewClient создаёт новый http.Client с Transport, использующим net.Dial из core/net.
+// Запросы клиента прерываются при отмене контекста выполнения плагина.
 func NewClient() (client *http.Client) {
 
 	return &http.Client{
-		Transport: NewTransport(),
+		Transport: &contextTransport{base: NewTransport()},
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"tgp/core/wasm"
 )
@@ -73,6 +74,15 @@ func Dispatch() {
 	// Создаём ResponseWriter
 	respWriter := newResponseWriter(requestID)
 
+	// panic в обработчике: клиент получает 500, плагин продолжает работу.
+	// Выполняется до host_finish_request (defer выше)
+	defer wasm.Recover("_dispatch", strconv.FormatUint(requestID, 10), func(report wasm.CrashReport) {
+		if !respWriter.written {
+			respWriter.WriteHeader(http.StatusInternalServerError)
+			_, _ = respWriter.Write([]byte(http.StatusText(http.StatusInternalServerError)))
+		}
+	})
+
 	// Вызываем обработчик
 	handler.ServeHTTP(respWriter, req)
 
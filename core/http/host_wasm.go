@@ -6,6 +6,9 @@ package http
 //go:wasmimport net host_listen_and_serve
 func hostListenAndServe(addrPtr uint32, addrLen uint32, handlerID uint64) (result uint64)
 
+//go:wasmimport net host_listen_and_serve_tls
+func hostListenAndServeTLS(addrPtr uint32, addrLen uint32, configPtr uint32, configLen uint32, handlerID uint64) (result uint64)
+
 //go:wasmimport net host_get_next_request
 func hostGetNextRequest(requestIDPtr uint32, handlerIDPtr uint32) (result uint64)
 
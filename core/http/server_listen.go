@@ -3,7 +3,11 @@
 // GENERATED BY 'T'ool 'G'ateway. DO NOT EDIT.
 package http
 
-import "net/http"
+import (
+	"net/http"
+
+	"tgp/core/net"
+)
 
 // ListenAndServe запускает HTTP сервер на указанном адресе.
 // Неблокирующая функция - возвращает управление сразу после запуска.
@@ -18,3 +22,20 @@ func ListenAndServe(addr string, handler http.Handler) (serverID uint64, err err
 
 	return 0, nil
 }
+
+// ListenAndServeTLSConfig запускает HTTPS сервер на указанном адресе с конфигурацией config.
+// Для локальной разработки config можно получить через net.SelfSignedTLSConfig.
+// В отличие от ListenAndServe, ошибка открытия адреса возвращается сразу.
+func ListenAndServeTLSConfig(addr string, config net.ServerTLSConfig, handler http.Handler) (serverID uint64, err error) {
+
+	var listener *net.Listener
+	if listener, err = net.ListenTLS(net.NetworkTCP, addr, config); err != nil {
+		return 0, err
+	}
+
+	go func() {
+		_ = http.Serve(listener, handler)
+	}()
+
+	return 0, nil
+}
@@ -4,9 +4,14 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 	"sync"
 
+	"github.com/goccy/go-json"
+
+	"tgp/core/i18n"
+	"tgp/core/net"
 	"tgp/core/wasm"
 )
 
@@ -42,6 +47,33 @@ func ListenAndServe(addr string, handler http.Handler) (serverID uint64, err err
 	return serverID, nil
 }
 
+// ListenAndServeTLSConfig запускает HTTPS сервер на указанном адресе с конфигурацией config.
+// TLS handshake выполняет хост. Для локальной разработки config можно получить через net.SelfSignedTLSConfig.
+// Неблокирующая функция - возвращает serverID для последующей остановки сервера через StopServerByID.
+func ListenAndServeTLSConfig(addr string, config net.ServerTLSConfig, handler http.Handler) (serverID uint64, err error) {
+
+	var configJSONBytes []byte
+	if configJSONBytes, err = json.Marshal(config); err != nil {
+		return 0, fmt.Errorf(i18n.Msg("failed to encode TLS config")+": %w", err)
+	}
+
+	handlerID := registerHandler(handler)
+
+	addrPtr, addrLen := wasm.StringToPtr(addr)
+	defer wasm.Free(addrPtr)
+
+	configPtr, configLen := wasm.StringToPtr(string(configJSONBytes))
+	defer wasm.Free(configPtr)
+
+	ret := hostListenAndServeTLS(addrPtr, addrLen, configPtr, configLen, handlerID)
+	if err = wasm.HandleHostError(ret); err != nil {
+		return 0, err
+	}
+
+	// ret содержит serverID в младших 32 битах
+	return uint64(uint32(ret)), nil
+}
+
 // registerHandler регистрирует обработчик и возвращает его ID.
 func registerHandler(handler http.Handler) (handlerID uint64) {
 
@@ -4,12 +4,25 @@
 package core
 
 import (
+	"log/slog"
+
+	"tgp/core/i18n"
+	"tgp/core/net"
 	"tgp/core/plugin"
+	"tgp/core/wasm"
 )
 
 // InitPlugin инициализирует плагин.
-// Для не-WASM сборок ничего не делает.
-func InitPlugin(plugin plugin.Plugin) {
+// Для не-WASM сборок применяет ограничения plugin.Info, которые в WASM проверяет хост.
+func InitPlugin(p plugin.Plugin) {
+
+	info, err := p.Info()
+	if err != nil {
+		slog.Error(i18n.Msg("failed to get plugin info"), slog.String("error", err.Error()))
+		return
+	}
 
-	// Для не-WASM сборок ничего не делаем
+	net.SetAllowedHosts(info.AllowedHosts)
+	net.SetAllowedPaths(info.AllowedPaths)
+	wasm.SetPluginInstance(p)
 }
@@ -31,13 +31,18 @@ type Conn struct {
 var _ net.Conn = &Conn{}
 
 // addr реализует net.Addr.
+// Пустой network означает NetworkTCP.
 type addr struct {
+	network string
 	address string
 }
 
 func (a *addr) Network() (network string) {
 
-	return NetworkTCP
+	if a.network == "" {
+		return NetworkTCP
+	}
+	return a.network
 }
 
 func (a *addr) String() (str string) {
@@ -59,7 +59,7 @@ func (c *Conn) RemoteAddr() (result net.Addr) {
 	}
 
 	address := string(addrBytes)
-	return &addr{address: address}
+	return &addr{network: c.network, address: address}
 }
 
 // LocalAddr возвращает локальный адрес соединения.
@@ -111,5 +111,5 @@ func (c *Conn) LocalAddr() (result net.Addr) {
 	}
 
 	address := string(addrBytes)
-	return &addr{address: address}
+	return &addr{network: c.network, address: address}
 }
@@ -5,7 +5,9 @@ package net
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 
@@ -16,56 +18,75 @@ import (
 )
 
 // Dial устанавливает соединение с удалённым адресом.
+// Для NetworkUnix address - путь к сокету; хост проверяет, что для него в AllowedPaths есть доступ на запись (connect требует права на запись),
+// для остальных сетей - по AllowedHosts.
+// Соединение не устанавливается, если контекст выполнения плагина отменён.
 func Dial(network, address string) (conn net.Conn, err error) {
 
+	ctx := wasm.Context()
+	if err = context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
 	connID, err := wasm.CallHostDial(conn_dial, network, address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Conn{id: uint64(connID), network: network}, nil
+	return contextConn(ctx, connID, network)
 }
 
 // DialContext устанавливает соединение с удалённым адресом с использованием контекста.
+// Установка соединения прерывается при отмене ctx или контекста выполнения плагина.
 func DialContext(ctx context.Context, network, address string) (conn net.Conn, err error) {
 
+	ctx, cancel := wasm.WithExecuteContext(ctx)
+	defer cancel()
+
+	if err = context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
 	connID, err := wasm.CallHostDialWithContext(ctx, conn_dial_context, network, address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Conn{id: uint64(connID), network: network}, nil
+	return contextConn(ctx, connID, network)
 }
 
 // DialTLS устанавливает TLS соединение с удалённым адресом.
 func DialTLS(network, address string) (conn net.Conn, err error) {
 
+	ctx := wasm.Context()
+	if err = context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
 	connID, err := wasm.CallHostDial(conn_dial_tls, network, address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Conn{id: uint64(connID), network: network}, nil
+	return contextConn(ctx, connID, network)
 }
 
 // DialTLSContext устанавливает TLS соединение с удалённым адресом с использованием контекста.
 func DialTLSContext(ctx context.Context, network, address string) (conn net.Conn, err error) {
 
+	ctx, cancel := wasm.WithExecuteContext(ctx)
+	defer cancel()
+
+	if err = context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
 	connID, err := wasm.CallHostDialWithContext(ctx, conn_dial_tls_context, network, address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Conn{id: uint64(connID), network: network}, nil
-}
-
-// TLSConfig представляет конфигурацию TLS для соединения.
-type TLSConfig struct {
-	MinVersion         string   `json:"min_version,omitempty"`          // "1.0", "1.1", "1.2", "1.3"
-	MaxVersion         string   `json:"max_version,omitempty"`          // "1.0", "1.1", "1.2", "1.3"
-	InsecureSkipVerify bool     `json:"insecure_skip_verify,omitempty"` // пропустить проверку сертификата
-	ServerName         string   `json:"server_name,omitempty"`          // имя сервера для SNI
-	CipherSuites       []string `json:"cipher_suites,omitempty"`        // список поддерживаемых cipher suites
+	return contextConn(ctx, connID, network)
 }
 
 // DialTLSWithConfig устанавливает TLS соединение с настраиваемой конфигурацией TLS.
@@ -109,7 +130,124 @@ func DialTLSWithConfig(network, address string, config TLSConfig) (conn net.Conn
 	}
 	connID := binary.LittleEndian.Uint32(connIDBytes)
 
-	return &Conn{id: uint64(connID), network: network}, nil
+	wasmConn := &Conn{id: uint64(connID), network: network}
+	if err = wasmConn.verifyPins(config.PinnedSPKI); err != nil {
+		return nil, err
+	}
+	return wasmConn, nil
+}
+
+// DialTLSWithConfigContext устанавливает TLS соединение с настраиваемой конфигурацией TLS с использованием контекста.
+// Установка соединения прерывается при отмене ctx или контекста выполнения плагина.
+func DialTLSWithConfigContext(ctx context.Context, network, address string, config TLSConfig) (conn net.Conn, err error) {
+
+	ctx, cancel := wasm.WithExecuteContext(ctx)
+	defer cancel()
+
+	if err = context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	var wasmConn *Conn
+	if wasmConn, err = dialTLSConfig(ctx, network, address, config); err != nil {
+		return nil, err
+	}
+	if err = wasmConn.verifyPins(config.PinnedSPKI); err != nil {
+		return nil, err
+	}
+	return wasmConn, nil
+}
+
+// DialTLSWithStdConfig устанавливает TLS соединение с конфигурацией crypto/tls
+// (например, http.Transport.TLSClientConfig). Если ServerName не задан, он берётся из address.
+// Handshake выполняет хост; если заданы RootCAs, VerifyPeerCertificate или VerifyConnection,
+// сертификаты сервера проверяются в плагине.
+func DialTLSWithStdConfig(ctx context.Context, network, address string, config *tls.Config) (conn net.Conn, err error) {
+
+	ctx, cancel := wasm.WithExecuteContext(ctx)
+	defer cancel()
+
+	if err = context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	config = config.Clone()
+	if config.ServerName == "" {
+		config.ServerName = serverNameFromAddress(address)
+	}
+
+	var hostConfig TLSConfig
+	if hostConfig, err = tlsConfigFromStd(config); err != nil {
+		return nil, err
+	}
+
+	var wasmConn *Conn
+	if wasmConn, err = dialTLSConfig(ctx, network, address, hostConfig); err != nil {
+		return nil, err
+	}
+	if err = wasmConn.verifyStd(config); err != nil {
+		return nil, err
+	}
+	return wasmConn, nil
+}
+
+// dialTLSConfig устанавливает TLS соединение через хост с дедлайном из ctx.
+func dialTLSConfig(ctx context.Context, network, address string, config TLSConfig) (conn *Conn, err error) {
+
+	configJSONBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.Msg("failed to encode TLS config")+": %w", err)
+	}
+
+	var deadline uint64
+	if d, ok := ctx.Deadline(); ok {
+		deadline = deadlineNanos(d)
+	}
+
+	networkPtr, networkLen := wasm.StringToPtr(network)
+	defer wasm.Free(networkPtr)
+
+	addressPtr, addressLen := wasm.StringToPtr(address)
+	defer wasm.Free(addressPtr)
+
+	configPtr, configLen := wasm.StringToPtr(string(configJSONBytes))
+	defer wasm.Free(configPtr)
+
+	connIDPtr := wasm.Malloc(4)
+	if connIDPtr == 0 {
+		return nil, errors.New(i18n.Msg("failed to allocate memory for connID"))
+	}
+	defer wasm.Free(connIDPtr)
+
+	ret := conn_dial_tls_with_config_context(deadline, networkPtr, networkLen, addressPtr, addressLen, configPtr, configLen, connIDPtr)
+	if err = wasm.HandleHostError(ret); err != nil {
+		return nil, err
+	}
+
+	connIDBytes := wasm.PtrToByte(connIDPtr, 4)
+	if len(connIDBytes) < 4 {
+		return nil, errors.New(i18n.Msg("invalid connID data size"))
+	}
+
+	var netConn net.Conn
+	if netConn, err = contextConn(ctx, binary.LittleEndian.Uint32(connIDBytes), network); err != nil {
+		return nil, err
+	}
+	return netConn.(*Conn), nil
+}
+
+// contextConn создаёт соединение по connID, полученному от хоста.
+// Вызов хоста синхронный, поэтому отмена контекста проверяется после него:
+// если ctx отменён во время установки соединения, соединение закрывается.
+func contextConn(ctx context.Context, connID uint32, network string) (conn net.Conn, err error) {
+
+	wasmConn := &Conn{id: uint64(connID), network: network}
+	if err = context.Cause(ctx); err != nil {
+		_ = wasmConn.Close()
+		return nil, err
+	}
+
+	return wasmConn, nil
 }
 
 // TLSHandshake выполняет TLS handshake для соединения.
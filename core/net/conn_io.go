@@ -125,7 +125,7 @@ func (c *Conn) Read(b []byte) (n int, err error) {
 	}
 
 	// Блокируем чтение до появления данных или до истечения deadline
-	n, err = wasm.AdaptivePollingRead(func() (ready bool, n int, err error) {
+	n, err = wasm.WaitRingBuffer(bufferPtr, wasm.RingBufferWriteIndex, func() (ready bool, n int, err error) {
 		readN, readErr := wasm.ReadFromRingBuffer(bufferPtr, c.readBufferDataSize, b)
 		if readErr != nil {
 			return false, readN, readErr
@@ -179,7 +179,7 @@ func (c *Conn) Write(b []byte) (n int, err error) {
 		// Буфер полон, блокируем до появления места или до истечения deadline
 		// ВАЖНО: bufio.Writer ожидает, что Write() либо запишет хотя бы 1 байт, либо вернет ошибку
 		// Поэтому мы не возвращаем 0 без ошибки, а блокируем до появления места
-		_, pollErr := wasm.AdaptivePollingRead(func() (ready bool, n int, err error) {
+		_, pollErr := wasm.WaitRingBuffer(bufferPtr, wasm.RingBufferReadIndex, func() (ready bool, n int, err error) {
 			// Проверяем, есть ли место для записи
 			written, writeErr := wasm.WriteToRingBuffer(bufferPtr, c.writeBufferDataSize, b[totalWritten:])
 			if writeErr != nil {
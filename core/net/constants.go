@@ -4,4 +4,8 @@ package net
 const (
 	// NetworkTCP - сетевой протокол TCP
 	NetworkTCP = "tcp"
+	// NetworkUDP - сетевой протокол UDP
+	NetworkUDP = "udp"
+	// NetworkUnix - Unix сокет; адрес - путь к сокету, доступ определяется AllowedPaths
+	NetworkUnix = "unix"
 )
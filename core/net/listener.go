@@ -18,6 +18,7 @@ import (
 type Listener struct {
 	id      uint64
 	address string
+	network string
 	mu      sync.Mutex
 	closed  bool
 	serving bool
@@ -30,6 +31,7 @@ func (l *Listener) ID() (id uint64) {
 }
 
 // Listen создаёт слушатель на указанном адресе.
+// Для NetworkUnix address - путь к сокету; хост проверяет, что для него в AllowedPaths есть доступ на запись.
 func Listen(network, address string) (listener *Listener, err error) {
 
 	// Преобразуем строки в указатели
@@ -65,6 +67,7 @@ func Listen(network, address string) (listener *Listener, err error) {
 	return &Listener{
 		id:      uint64(listenerID),
 		address: address,
+		network: network,
 	}, nil
 }
 
@@ -98,7 +101,7 @@ func (l *Listener) Addr() (a net.Addr) {
 	// Выделяем память для адреса (256 байт должно быть достаточно)
 	addrPtr := wasm.Malloc(256)
 	if addrPtr == 0 {
-		return &addr{address: l.address}
+		return &addr{network: l.network, address: l.address}
 	}
 	defer wasm.Free(addrPtr)
 
@@ -106,7 +109,7 @@ func (l *Listener) Addr() (a net.Addr) {
 	// addrLenPtr используется как входной (размер буфера) и выходной (реальная длина)
 	addrLenPtr := wasm.Malloc(4)
 	if addrLenPtr == 0 {
-		return &addr{address: l.address}
+		return &addr{network: l.network, address: l.address}
 	}
 	defer wasm.Free(addrLenPtr)
 
@@ -121,30 +124,30 @@ func (l *Listener) Addr() (a net.Addr) {
 	// Обрабатываем ошибку
 	if err := wasm.HandleHostError(ret); err != nil {
 		// При ошибке возвращаем кэшированный адрес
-		return &addr{address: l.address}
+		return &addr{network: l.network, address: l.address}
 	}
 
 	// Читаем реальную длину адреса из addrLenPtr (выходной параметр)
 	addrLenBytes := wasm.PtrToByte(addrLenPtr, 4)
 	if len(addrLenBytes) < 4 {
-		return &addr{address: l.address}
+		return &addr{network: l.network, address: l.address}
 	}
 	addrLen := binary.LittleEndian.Uint32(addrLenBytes)
 
 	if addrLen == 0 {
-		return &addr{address: l.address}
+		return &addr{network: l.network, address: l.address}
 	}
 
 	// Читаем адрес из памяти
 	addrBytes := wasm.PtrToByte(addrPtr, addrLen)
 	if len(addrBytes) == 0 {
-		return &addr{address: l.address}
+		return &addr{network: l.network, address: l.address}
 	}
 
 	address := string(addrBytes)
 	// Обновляем кэшированный адрес
 	l.address = address
-	return &addr{address: address}
+	return &addr{network: l.network, address: address}
 }
 
 // Accept ожидает и возвращает следующее соединение от слушателя.
@@ -186,7 +189,7 @@ func (l *Listener) Accept() (conn net.Conn, err error) {
 	// Создаём Conn из connID
 	return &Conn{
 		id:      uint64(connID),
-		network: NetworkTCP,
+		network: l.network,
 	}, nil
 }
 
@@ -59,3 +59,36 @@ func listener_close(listenerID uint64) uint64
 
 //go:wasmimport net listener_addr
 func listener_addr(listenerID uint64, addrPtr, addrLenPtr uint32) uint64
+
+//go:wasmimport net packet_listen
+func packet_listen(networkPtr, networkLen, addressPtr, addressLen, connIDPtr uint32) uint64
+
+//go:wasmimport net packet_dial
+func packet_dial(networkPtr, networkLen, addressPtr, addressLen, connIDPtr uint32) uint64
+
+//go:wasmimport net packet_read_from
+func packet_read_from(connID uint64, deadline uint64, bufPtr, bufLen, nPtr, addrPtr, addrLenPtr uint32) uint64
+
+//go:wasmimport net packet_write_to
+func packet_write_to(connID uint64, deadline uint64, bufPtr, bufLen, addressPtr, addressLen, nPtr uint32) uint64
+
+//go:wasmimport net packet_close
+func packet_close(connID uint64) uint64
+
+//go:wasmimport net packet_local_addr
+func packet_local_addr(connID uint64, addrPtr, addrLenPtr uint32) uint64
+
+//go:wasmimport net packet_remote_addr
+func packet_remote_addr(connID uint64, addrPtr, addrLenPtr uint32) uint64
+
+//go:wasmimport net dns_lookup
+func dns_lookup(deadline uint64, requestPtr, requestLen, resultPtrPtr, resultSizePtr uint32) uint64
+
+//go:wasmimport net listener_listen_tls
+func listener_listen_tls(networkPtr, networkLen, addressPtr, addressLen, configPtr, configLen, listenerIDPtr uint32) uint64
+
+//go:wasmimport net conn_dial_tls_with_config_context
+func conn_dial_tls_with_config_context(deadline uint64, networkPtr, networkLen, addressPtr, addressLen, configPtr, configLen, connIDPtr uint32) uint64
+
+//go:wasmimport net conn_tls_state
+func conn_tls_state(connID uint64, resultPtrPtr, resultSizePtr uint32) uint64
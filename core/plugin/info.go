@@ -26,6 +26,7 @@ type Info struct {
 	Options []Option `json:"options"`
 	// AllowedHosts - белый список хостов для сетевых подключений (только для WASM плагинов).
 	// Если пусто, плагин не может выполнять сетевые подключения.
+	// Для UDP (net.DialUDP, net.PacketConn.WriteTo) проверяется адрес получателя каждой датаграммы.
 	// Поддерживаемые форматы:
 	// - Доменные имена: точное совпадение ("api.example.com") или wildcard ("*.example.com")
 	// - IP-адреса: точное совпадение ("192.168.1.1", "::1", "[2001:db8::1]") или CIDR ("192.168.1.0/24", "2001:db8::/32")
@@ -86,6 +87,7 @@ type Info struct {
 	// Также поддерживаются специальные префиксы: @root (корень проекта), @go (GOPATH).
 	// Значение - уровень доступа: "r" (только чтение), "w" (чтение и запись, полный доступ).
 	// Пример: {"internal/cli": "w", "$HOME/.config": "r", "~/data": "w", "/tmp/cache": "w", "@root/pkg": "r"}
+	// Также определяет доступ к Unix сокетам: для net.Dial("unix", path) и net.Listen нужен "w" (connect требует права на запись в сокет).
 	AllowedPaths map[string]string `json:"allowedPaths,omitempty"`
 }
 
@@ -105,7 +107,8 @@ type Option struct {
 	Name string `json:"name"`
 	// Short - краткое имя настройки (используется как -s в CLI, опционально).
 	Short string `json:"short,omitempty"`
-	// Type - тип значения настройки (string, int, bool и т.д.).
+	// Type - тип значения настройки (см. константы Type*).
+	// Неизвестные типы не проверяются валидатором.
 	Type string `json:"type"`
 	// Description - описание настройки.
 	Description string `json:"description"`
@@ -117,4 +120,38 @@ type Option struct {
 	// IsPositional - true для позиционных аргументов (не флагов).
 	// Позиционные аргументы передаются без флагов, например: `tg plugin install <package>`.
 	IsPositional bool `json:"isPositional,omitempty"`
+	// Enum - допустимые значения настройки (для TypeList проверяется каждый элемент).
+	Enum []string `json:"enum,omitempty"`
+	// Min - минимальное значение для TypeInt/TypeFloat или минимальное количество элементов для TypeList.
+	Min *float64 `json:"min,omitempty"`
+	// Max - максимальное значение для TypeInt/TypeFloat или максимальное количество элементов для TypeList.
+	Max *float64 `json:"max,omitempty"`
+	// Pattern - регулярное выражение (синтаксис Go regexp), которому должно соответствовать значение.
+	// Для TypeList проверяется каждый элемент.
+	Pattern string `json:"pattern,omitempty"`
+	// Env - имя переменной окружения, из которой берётся значение, если настройка не передана.
+	// Переменная должна быть перечислена в AllowedEnvVars.
+	Env string `json:"env,omitempty"`
+	// Deprecated - сообщение об устаревании настройки. Если не пусто, при использовании выводится предупреждение.
+	Deprecated string `json:"deprecated,omitempty"`
 }
+
+// Типы значений настроек, поддерживаемые валидатором.
+const (
+	// TypeString - строка.
+	TypeString = "string"
+	// TypeInt - целое число (JSON число или строка "42").
+	TypeInt = "int"
+	// TypeFloat - число с плавающей точкой.
+	TypeFloat = "float"
+	// TypeBool - логическое значение (JSON bool или строка "true"/"false").
+	TypeBool = "bool"
+	// TypeDuration - длительность в формате time.ParseDuration ("30s", "1m30s").
+	TypeDuration = "duration"
+	// TypePath - путь файловой системы (нормализуется через filepath.Clean).
+	TypePath = "path"
+	// TypeURL - абсолютный URL со схемой и хостом.
+	TypeURL = "url"
+	// TypeList - список строк (JSON массив или строка через запятую).
+	TypeList = "list"
+)
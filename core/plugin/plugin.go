@@ -2,6 +2,8 @@
 package plugin
 
 import (
+	"context"
+
 	"tgp/core/data"
 )
 
@@ -10,3 +12,12 @@ type Plugin interface {
 	Info() (info Info, err error)
 	Execute(rootDir string, request data.Storage, path ...string) (response data.Storage, err error)
 }
+
+// ContextExecutor определяет необязательный интерфейс плагина с поддержкой отмены.
+// Если плагин реализует ContextExecutor, хост вызывает ExecuteContext вместо Execute.
+// ctx отменяется, когда хост вызывает экспорт cancel (например, при Ctrl+C).
+// ctx не отменяется при возврате из ExecuteContext: фоновая работа плагина
+// (HTTP сервер, задачи) может подписаться на отмену через context.AfterFunc.
+type ContextExecutor interface {
+	ExecuteContext(ctx context.Context, rootDir string, request data.Storage, path ...string) (response data.Storage, err error)
+}
@@ -5,13 +5,17 @@
 package wasm
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"time"
 
 	"github.com/goccy/go-json"
 
+	"tgp/core/errs"
 	"tgp/core/i18n"
 )
 
@@ -25,6 +29,60 @@ func hostExecuteCommand(commandPtr uint32, commandLen uint32, argsPtr uint32, ar
 //go:wasmimport command host_get_stream_read_buffer_ptr
 func hostGetStreamReadBufferPtr(streamID uint32, bufferPtrPtr uint32) (resultCode uint32)
 
+// hostExecuteCommandRequest вызывает host_execute_command_request из модуля command.
+// Запрос передаётся в JSON (CommandRequest), результат - CommandResponse.
+//
+//go:wasmimport command host_execute_command_request
+func hostExecuteCommandRequest(requestPtr uint32, requestLen uint32, resultPtrPtr uint32, resultSizePtr uint32) (resultCode uint32)
+
+// hostExecutePipeline вызывает host_execute_pipeline из модуля command.
+// Запрос передаётся в JSON (PipelineRequest), результат - CommandResponse конвейера.
+// Хост проверяет каждый этап по AllowedShellCMDs до запуска первого из них.
+// Импорт обязательный: без него хост не сможет инстанцировать модуль плагина.
+//
+//go:wasmimport command host_execute_pipeline
+func hostExecutePipeline(requestPtr uint32, requestLen uint32, resultPtrPtr uint32, resultSizePtr uint32) (resultCode uint32)
+
+// hostGetStreamWriteBufferPtr получает указатель на кольцевой буфер для записи в поток (WASM → хост).
+//
+//go:wasmimport command host_get_stream_write_buffer_ptr
+func hostGetStreamWriteBufferPtr(streamID uint32, bufferPtrPtr uint32) (resultCode uint32)
+
+// hostCloseStream закрывает поток команды.
+// Для stdin хост дочитывает оставшиеся в буфере данные и закрывает stdin процесса.
+// Для stdout/stderr хост прекращает запись в поток и освобождает буфер; CommandResponse
+// по stdoutStreamID остаётся доступен до завершения команды.
+//
+//go:wasmimport command host_close_stream
+func hostCloseStream(streamID uint32) (resultCode uint32)
+
+// hostSignalCommand отправляет сигнал signal (номер POSIX) команде, запущенной с потоком stdoutStreamID.
+// Возвращает один из статусов signal*.
+// Импорт обязательный: без него хост не сможет инстанцировать модуль плагина;
+// хост, не умеющий отправлять сигнал, возвращает signalUnsupported.
+//
+//go:wasmimport command host_signal_command
+func hostSignalCommand(stdoutStreamID uint32, signal uint32) (status uint32)
+
+// hostResizePTY изменяет размер псевдотерминала команды, запущенной с потоком stdoutStreamID.
+// Импорт обязательный: без него хост не сможет инстанцировать модуль плагина.
+//
+//go:wasmimport command host_resize_pty
+func hostResizePTY(stdoutStreamID uint32, rows uint32, cols uint32) (resultCode uint32)
+
+// hostLookPath ищет исполняемый файл в PATH хоста. Результат - LookPathResponse в JSON.
+//
+//go:wasmimport command host_look_path
+func hostLookPath(namePtr uint32, nameLen uint32, resultPtrPtr uint32, resultSizePtr uint32) (resultCode uint32)
+
+// Статусы host_signal_command.
+const (
+	signalSent        = 0
+	signalFailed      = 1
+	signalProcessDone = 2
+	signalUnsupported = 3
+)
+
 // hostGetCommandResponse получает обновленный CommandResponse по stdoutStreamID.
 //
 //go:wasmimport command host_get_command_response
@@ -61,15 +119,116 @@ func ExecuteCommandInDir(command string, args []string, workDir string) (respons
 
 	// Вызываем функцию хоста
 	if hostExecuteCommand(commandPtr, commandLen, argsPtr, argsLen, workDirPtr, workDirLen, resultPtrPtr, resultSizePtr) != 0 {
-		return nil, fmt.Errorf(i18n.Msg("failed to execute command"))
+		return nil, commandCallError(resultPtrPtr, resultSizePtr)
+	}
+
+	return readCommandResponse(resultPtrPtr, resultSizePtr)
+}
+
+// ExecuteCommandRequest выполняет команду через хост по расширенному запросу.
+// Используется, когда нужны параметры, не поддерживаемые host_execute_command (например, stdin).
+func ExecuteCommandRequest(request CommandRequest) (response *CommandResponse, err error) {
+
+	return callCommandRequest(hostExecuteCommandRequest, request)
+}
+
+// ExecutePipeline запускает конвейер команд через хост.
+// Возвращает CommandResponse конвейера: ожидание, сигналы и потоки работают так же, как для одной команды.
+func ExecutePipeline(request PipelineRequest) (response *CommandResponse, err error) {
+
+	return callCommandRequest(hostExecutePipeline, request)
+}
+
+// callCommandRequest кодирует request в JSON, вызывает функцию хоста hostCall и декодирует CommandResponse.
+func callCommandRequest(hostCall func(requestPtr uint32, requestLen uint32, resultPtrPtr uint32, resultSizePtr uint32) uint32, request any) (response *CommandResponse, err error) {
+
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.Msg("failed to encode request")+": %w", err)
+	}
+
+	requestPtr, requestLen := StringToPtr(string(requestBytes))
+	defer Free(requestPtr)
+
+	// Выделяем память для указателей результата
+	resultPtrPtr := Malloc(4)
+	resultSizePtr := Malloc(4)
+	defer Free(resultPtrPtr)
+	defer Free(resultSizePtr)
+
+	if hostCall(requestPtr, requestLen, resultPtrPtr, resultSizePtr) != 0 {
+		return nil, commandCallError(resultPtrPtr, resultSizePtr)
+	}
+
+	if response, err = readCommandResponse(resultPtrPtr, resultSizePtr); err != nil {
+		return nil, err
+	}
+
+	if response.StdinStreamID < 0 {
+		return nil, fmt.Errorf(i18n.Msg("invalid stdin stream ID: negative value %d"), response.StdinStreamID)
 	}
 
+	return response, nil
+}
+
+// LookPath ищет исполняемый файл name в PATH хоста и возвращает путь к нему.
+// Если файл не найден, возвращает ошибку с кодом errs.NotFound.
+func LookPath(name string) (path string, err error) {
+
+	namePtr, nameLen := StringToPtr(name)
+	defer Free(namePtr)
+
+	resultPtrPtr := Malloc(4)
+	resultSizePtr := Malloc(4)
+	defer Free(resultPtrPtr)
+	defer Free(resultSizePtr)
+
+	if hostLookPath(namePtr, nameLen, resultPtrPtr, resultSizePtr) != 0 {
+		return "", commandCallError(resultPtrPtr, resultSizePtr)
+	}
+
+	resultPtr := binary.LittleEndian.Uint32(PtrToByte(resultPtrPtr, 4))
+	resultSize := binary.LittleEndian.Uint32(PtrToByte(resultSizePtr, 4))
+	if resultSize == 0 {
+		return "", errors.New(i18n.Msg("empty response from host"))
+	}
+	resultBytes := PtrToByte(resultPtr, resultSize)
+	defer Free(resultPtr)
+
+	var response LookPathResponse
+	if err = json.Unmarshal(resultBytes, &response); err != nil {
+		return "", fmt.Errorf(i18n.Msg("failed to decode response")+": %w", err)
+	}
+	if response.Error != "" {
+		return "", errs.Decode(response.Error, response.Code, response.Details)
+	}
+	return response.Path, nil
+}
+
+// commandCallError возвращает ошибку неудачного вызова функции хоста команд.
+// Если хост записал ошибку в результат (hostError в JSON), она восстанавливается с кодом,
+// иначе возвращается общая ошибка запуска команды.
+func commandCallError(resultPtrPtr uint32, resultSizePtr uint32) (err error) {
+
+	resultPtr := binary.LittleEndian.Uint32(PtrToByte(resultPtrPtr, 4))
+	resultSize := binary.LittleEndian.Uint32(PtrToByte(resultSizePtr, 4))
+	if resultPtr == 0 || resultSize == 0 {
+		return errors.New(i18n.Msg("failed to execute command"))
+	}
+	defer Free(resultPtr)
+
+	return decodeHostError(PtrToByte(resultPtr, resultSize))
+}
+
+// readCommandResponse читает и декодирует CommandResponse, записанный хостом по resultPtrPtr/resultSizePtr.
+func readCommandResponse(resultPtrPtr uint32, resultSizePtr uint32) (response *CommandResponse, err error) {
+
 	// Читаем указатель и размер результата
 	resultPtr := binary.LittleEndian.Uint32(PtrToByte(resultPtrPtr, 4))
 	resultSize := binary.LittleEndian.Uint32(PtrToByte(resultSizePtr, 4))
 
 	if resultSize == 0 {
-		return nil, fmt.Errorf(i18n.Msg("empty response from host"))
+		return nil, errors.New(i18n.Msg("empty response from host"))
 	}
 
 	// Читаем результат
@@ -78,7 +237,7 @@ func ExecuteCommandInDir(command string, args []string, workDir string) (respons
 
 	// Декодируем JSON напрямую в CommandResponse
 	response = &CommandResponse{}
-	if err := json.Unmarshal(resultBytes, response); err != nil {
+	if err = json.Unmarshal(resultBytes, response); err != nil {
 		return nil, fmt.Errorf(i18n.Msg("failed to decode response")+": %w", err)
 	}
 
@@ -99,6 +258,7 @@ type StreamReader struct {
 	streamID          uint32
 	bufferDataSize    uint32 // Кэшированный размер области данных (константа)
 	bufferDataSizeSet bool
+	closed            bool
 }
 
 // NewStreamReader создает новый StreamReader для чтения из потока.
@@ -113,6 +273,10 @@ func NewStreamReader(streamID uint32) (reader *StreamReader) {
 // Блокирует до появления данных или до закрытия потока.
 func (r *StreamReader) Read(p []byte) (n int, err error) {
 
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
 	if len(p) == 0 {
 		return 0, nil
 	}
@@ -134,7 +298,7 @@ func (r *StreamReader) Read(p []byte) (n int, err error) {
 	}
 
 	// Блокируем чтение до появления данных или до закрытия потока
-	n, err = AdaptivePollingRead(func() (ready bool, n int, err error) {
+	n, err = WaitRingBuffer(bufferPtr, RingBufferWriteIndex, func() (ready bool, n int, err error) {
 		// Пытаемся прочитать данные из кольцевого буфера
 		nRead, readErr := ReadFromRingBuffer(bufferPtr, r.bufferDataSize, p)
 		if readErr != nil {
@@ -157,13 +321,47 @@ func (r *StreamReader) Read(p []byte) (n int, err error) {
 			return false, 0, io.EOF
 		}
 
-		// Буфер пуст и поток не закрыт, продолжаем polling
+		// Буфер пуст и поток не закрыт, продолжаем ожидание
 		return false, 0, nil
 	}, time.Time{})
 
 	return n, err
 }
 
+// SignalCommand отправляет сигнал команде, запущенной с потоком stdoutStreamID.
+// Для завершившейся команды возвращает os.ErrProcessDone.
+func SignalCommand(stdoutStreamID uint32, signal uint32) (err error) {
+
+	switch hostSignalCommand(stdoutStreamID, signal) {
+	case signalSent:
+		return nil
+	case signalProcessDone:
+		return os.ErrProcessDone
+	case signalUnsupported:
+		return fmt.Errorf(i18n.Msg("signal %d is not supported by host")+": %w", signal, errors.ErrUnsupported)
+	default:
+		return fmt.Errorf(i18n.Msg("failed to send signal %d"), signal)
+	}
+}
+
+// ResizeTerminal изменяет размер псевдотерминала команды, запущенной с потоком stdoutStreamID.
+func ResizeTerminal(stdoutStreamID uint32, rows uint16, cols uint16) (err error) {
+
+	if hostResizePTY(stdoutStreamID, uint32(rows), uint32(cols)) != 0 {
+		return errors.New(i18n.Msg("failed to resize terminal"))
+	}
+	return nil
+}
+
+// CloseStream закрывает поток команды в хосте и освобождает его буфер.
+func CloseStream(streamID uint32) (err error) {
+
+	if hostCloseStream(streamID) != 0 {
+		return errors.New(i18n.Msg("failed to close stream"))
+	}
+	return nil
+}
+
 // getStreamReadBufferPtr получает указатель на кольцевой буфер для чтения из потока.
 func getStreamReadBufferPtr(streamID uint32) (bufferPtr uint32, err error) {
 
@@ -201,12 +399,168 @@ func getStreamReadBufferPtr(streamID uint32) (bufferPtr uint32, err error) {
 	return 0, fmt.Errorf(i18n.Msg("failed to get stream read buffer ptr after %d retries"), maxRetries)
 }
 
-// Close закрывает поток.
+// lookupStreamReadBufferPtr получает указатель на буфер чтения потока за одну попытку.
+// Возвращает 0, если поток не найден (ещё не создан, закрыт или освобождён).
+func lookupStreamReadBufferPtr(streamID uint32) (bufferPtr uint32) {
+
+	bufferPtrPtr := Malloc(4)
+	if bufferPtrPtr == 0 {
+		return 0
+	}
+	defer Free(bufferPtrPtr)
+
+	if hostGetStreamReadBufferPtr(streamID, bufferPtrPtr) != 0 {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(PtrToByte(bufferPtrPtr, 4))
+}
+
+// Close закрывает поток: хост прекращает запись и освобождает буфер.
+// Команда продолжает выполняться; её дальнейший вывод в этот поток отбрасывается.
+// Повторный вызов ничего не делает.
 func (r *StreamReader) Close() (err error) {
 
-	// Закрываем поток через хост (если нужно)
-	// Пока просто возвращаем nil, так как хост сам закроет поток при завершении команды
-	return nil
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	return CloseStream(r.streamID)
+}
+
+// StreamWriter реализует io.WriteCloser для записи данных в поток через кольцевой буфер (WASM → хост).
+// Используется для stdin команды.
+type StreamWriter struct {
+	streamID       uint32
+	bufferPtr      uint32
+	bufferDataSize uint32 // Кэшированный размер области данных (константа)
+	closed         bool
+}
+
+// NewStreamWriter создает новый StreamWriter для записи в поток.
+func NewStreamWriter(streamID uint32) (writer *StreamWriter) {
+
+	return &StreamWriter{
+		streamID: streamID,
+	}
+}
+
+// Write записывает данные в поток через кольцевой буфер.
+// Блокирует до появления места в буфере. Если хост закрыл поток (процесс завершился),
+// возвращает io.ErrClosedPipe.
+func (w *StreamWriter) Write(p []byte) (n int, err error) {
+
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if w.bufferPtr == 0 {
+		if w.bufferPtr, err = getStreamWriteBufferPtr(w.streamID); err != nil {
+			return 0, fmt.Errorf(i18n.Msg("failed to get buffer ptr")+": %w", err)
+		}
+		var header RingBufferHeader
+		if header, err = ReadRingBufferHeader(w.bufferPtr); err != nil {
+			return 0, fmt.Errorf(i18n.Msg("failed to read header for data size")+": %w", err)
+		}
+		w.bufferDataSize = header.DataSize
+	}
+
+	for n < len(p) {
+		// Блокируем запись до появления места в буфере (хост сдвигает ReadIndex)
+		offset := n
+		written, waitErr := WaitRingBuffer(w.bufferPtr, RingBufferReadIndex, func() (ready bool, written int, err error) {
+			if written, err = WriteToRingBuffer(w.bufferPtr, w.bufferDataSize, p[offset:]); err != nil {
+				return false, 0, err
+			}
+			return written > 0, written, nil
+		}, time.Time{})
+		if waitErr != nil {
+			if errors.Is(waitErr, io.EOF) {
+				return n, io.ErrClosedPipe
+			}
+			return n, waitErr
+		}
+		n += written
+	}
+
+	return n, nil
+}
+
+// Close закрывает поток: хост дочитывает данные из буфера и закрывает stdin процесса.
+// Повторный вызов ничего не делает.
+func (w *StreamWriter) Close() (err error) {
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return CloseStream(w.streamID)
+}
+
+// getStreamWriteBufferPtr получает указатель на кольцевой буфер для записи в поток.
+func getStreamWriteBufferPtr(streamID uint32) (bufferPtr uint32, err error) {
+
+	bufferPtrPtr := Malloc(4)
+	if bufferPtrPtr == 0 {
+		return 0, errors.New(i18n.Msg("failed to allocate memory for bufferPtr"))
+	}
+	defer Free(bufferPtrPtr)
+
+	if hostGetStreamWriteBufferPtr(streamID, bufferPtrPtr) != 0 {
+		return 0, errors.New(i18n.Msg("failed to get stream write buffer ptr"))
+	}
+
+	bufferPtrData := PtrToByte(bufferPtrPtr, 4)
+	if len(bufferPtrData) < 4 {
+		return 0, errors.New(i18n.Msg("invalid bufferPtr data size"))
+	}
+
+	if bufferPtr = binary.LittleEndian.Uint32(bufferPtrData); bufferPtr == 0 {
+		return 0, errors.New(i18n.Msg("failed to get stream write buffer ptr"))
+	}
+	return bufferPtr, nil
+}
+
+// commandRunning - значение ExitCode, означающее, что команда ещё выполняется.
+const commandRunning = -2
+
+// maxCommandResponseErrors - число подряд неудачных запросов CommandResponse, после которого ожидание прекращается.
+const maxCommandResponseErrors = 100
+
+// WaitCommandResponse ожидает завершения команды и возвращает CommandResponse с кодом выхода.
+// Между запросами ожидает закрытия потока stdout через host_wait_buffer (или короткую паузу,
+// если буфер уже освобождён). Прерывается при отмене ctx.
+func WaitCommandResponse(ctx context.Context, stdoutStreamID uint32) (response *CommandResponse, err error) {
+
+	failures := 0
+	for {
+		if response, err = GetCommandResponse(stdoutStreamID); err == nil {
+			failures = 0
+			if response.ExitCode != commandRunning {
+				return response, nil
+			}
+		} else if failures++; failures >= maxCommandResponseErrors {
+			return nil, err
+		}
+
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+
+		// Буфер запрашивается на каждой итерации: после завершения команды или закрытия
+		// потока (StreamReader.Close) хост может его освободить
+		bufferPtr := lookupStreamReadBufferPtr(stdoutStreamID)
+
+		// Ждём закрытия stdout; если поток уже закрыт или буфер освобождён, делаем паузу
+		closed := uint32(1)
+		if bufferPtr != 0 {
+			closed = readBufferField(bufferPtr, RingBufferClosed)
+		}
+		if closed != 0 || !waitBufferField(bufferPtr, RingBufferClosed, 0, maxWaitSlice) {
+			time.Sleep(time.Millisecond * 5)
+		}
+	}
 }
 
 // GetCommandResponse получает обновленный CommandResponse по stdoutStreamID.
@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"log/slog"
+	"strconv"
 
 	"github.com/goccy/go-json"
 
@@ -36,6 +37,11 @@ func onNewConnectionHandler(ptr uint32, size uint32) (result uint64) {
 	listenerID := binary.LittleEndian.Uint64(dataBytes[0:8])
 	connID := binary.LittleEndian.Uint64(dataBytes[8:16])
 
+	// panic в обработчике соединения не должна останавливать плагин
+	defer Recover("on_new_connection", strconv.FormatUint(connID, 10), func(report CrashReport) {
+		result = createErrorResult(report.Err())
+	})
+
 	// Вызываем обработку соединения напрямую
 	// handleConnection находится в пакете net для избежания циклического импорта
 	// ВАЖНО: вызываем напрямую, так как CallChannel уже обрабатывает вызовы последовательно
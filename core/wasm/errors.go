@@ -4,6 +4,21 @@
 
 package wasm
 
+import (
+	"github.com/goccy/go-json"
+
+	"tgp/core/errs"
+)
+
+// hostError представляет ошибку в JSON формате, передаваемую между плагином и хостом.
+// Поле error поддерживается для совместимости со старыми версиями хоста.
+type hostError struct {
+	Code    errs.Code      `json:"code,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
 // HandleHostError обрабатывает uint64 результат от host функции
 // и возвращает ошибку, если она есть.
 // Формат результата: верхние 32 бита - указатель, нижние 32 бита - размер (31-й бит - флаг ошибки).
@@ -16,6 +31,41 @@ func HandleHostError(result uint64) (err error) {
 	return retUint64ToError(result)
 }
 
+// decodeHostError восстанавливает ошибку хоста с кодом (*errs.Error).
+// Поддерживает JSON формат hostError и простой текст (код errs.Unknown).
+func decodeHostError(errBytes []byte) (err error) {
+
+	var he hostError
+	if json.Unmarshal(errBytes, &he) == nil && (he.Message != "" || he.Error != "") {
+		message := he.Message
+		if message == "" {
+			message = he.Error
+		}
+		return errs.Decode(message, he.Code, he.Details)
+	}
+	return errs.Decode(string(errBytes), errs.Unknown, nil)
+}
+
+// createErrorResult создает uint64 результат с ошибкой err в JSON формате hostError.
+func createErrorResult(err error) (result uint64) {
+
+	e := errs.From(err)
+	errorBytes, _ := json.Marshal(hostError{
+		Code:    e.Code,
+		Message: e.Message,
+		Error:   e.Message,
+		Details: e.Details,
+	})
+	return createErrorResultFromBytes(errorBytes)
+}
+
+// responseError раскладывает ошибку на поля ответа (error, code, details).
+func responseError(err error) (message string, code errs.Code, details map[string]any) {
+
+	e := errs.From(err)
+	return e.Message, e.Code, e.Details
+}
+
 // createErrorResultFromBytes создает uint64 результат с ошибкой из байтов.
 func createErrorResultFromBytes(errBytes []byte) (result uint64) {
 
@@ -8,6 +8,7 @@ import (
 	"github.com/goccy/go-json"
 
 	"tgp/core/data"
+	"tgp/core/errs"
 )
 
 // executeRequest представляет запрос на выполнение плагина.
@@ -18,8 +19,11 @@ type executeRequest struct {
 }
 
 // executeResponse представляет ответ на выполнение плагина.
+// Code и Details сохраняют код ошибки плагина для отображения хостом.
 type executeResponse struct {
 	Error    string          `json:"error,omitempty"`
+	Code     errs.Code       `json:"code,omitempty"`
+	Details  map[string]any  `json:"details,omitempty"`
 	Response data.MapStorage `json:"response,omitempty"`
 }
 
@@ -31,16 +35,63 @@ type infoResponse struct {
 // taskResponse представляет ответ от хоста при работе с задачами.
 type taskResponse struct {
 	Error    string          `json:"error,omitempty"`
+	Code     errs.Code       `json:"code,omitempty"`
+	Details  map[string]any  `json:"details,omitempty"`
 	Response json.RawMessage `json:"response,omitempty"`
 }
 
+// CommandRequest представляет запрос на запуск команды через host_execute_command_request.
+// В отличие от host_execute_command, новые параметры добавляются полями без изменения импорта.
+type CommandRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	WorkDir string   `json:"workDir"`
+	// Env - окружение команды в формате "KEY=value"; null - окружение хоста по умолчанию.
+	// Имена переменных должны быть перечислены в AllowedEnvVars.
+	Env []string `json:"env"`
+	// Stdin - хост создаёт поток stdin (WASM → хост) и возвращает его в StdinStreamID.
+	Stdin bool `json:"stdin,omitempty"`
+	// PTY - хост запускает команду в псевдотерминале указанного размера.
+	// Ввод терминала передаётся через StdinStreamID, вывод (stdout и stderr) - через StdoutStreamID.
+	PTY *TerminalSize `json:"pty,omitempty"`
+}
+
+// TerminalSize - размер псевдотерминала.
+type TerminalSize struct {
+	Rows uint16 `json:"rows"`
+	Cols uint16 `json:"cols"`
+}
+
+// PipelineRequest представляет запрос на запуск конвейера команд через host_execute_pipeline.
+// Хост соединяет stdout каждого этапа со stdin следующего напрямую, минуя память WASM.
+// В ответе StdinStreamID относится к первому этапу (Stages[0].Stdin), StdoutStreamID - к последнему,
+// StderrStreamID объединяет stderr всех этапов.
+type PipelineRequest struct {
+	Stages []CommandRequest `json:"stages"`
+}
+
+// LookPathResponse представляет результат поиска исполняемого файла через host_look_path.
+// Если файл не найден в PATH хоста, Error заполнен, а Code равен errs.NotFound.
+type LookPathResponse struct {
+	Path    string         `json:"path,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Code    errs.Code      `json:"code,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
 // CommandResponse представляет результат выполнения команды через хост.
 // Stdout и Stderr передаются через потоки (streamID), а не кодируются.
 type CommandResponse struct {
-	ExitCode       int    `json:"exitCode"`
-	StdoutStreamID int32  `json:"stdoutStreamID,omitempty"`
-	StderrStreamID int32  `json:"stderrStreamID,omitempty"`
-	Error          string `json:"error,omitempty"`
+	ExitCode int `json:"exitCode"`
+	// ExitCodes - коды выхода этапов конвейера (только для host_execute_pipeline).
+	// ExitCode конвейера - код последнего этапа, завершившегося с ненулевым кодом.
+	ExitCodes      []int          `json:"exitCodes,omitempty"`
+	StdinStreamID  int32          `json:"stdinStreamID,omitempty"`
+	StdoutStreamID int32          `json:"stdoutStreamID,omitempty"`
+	StderrStreamID int32          `json:"stderrStreamID,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	Code           errs.Code      `json:"code,omitempty"`
+	Details        map[string]any `json:"details,omitempty"`
 }
 
 // interactiveSelectConfig представляет конфигурацию интерактивного выбора.
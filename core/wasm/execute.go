@@ -5,6 +5,7 @@
 package wasm
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/goccy/go-json"
@@ -14,6 +15,9 @@ import (
 	"tgp/core/plugin"
 )
 
+// ErrPluginNotSet возвращается PluginInfo, если экземпляр плагина не установлен.
+var ErrPluginNotSet = errors.New(i18n.Msg("plugin instance not set"))
+
 var pluginInstance plugin.Plugin
 
 // SetPluginInstance устанавливает экземпляр плагина.
@@ -22,6 +26,15 @@ func SetPluginInstance(p plugin.Plugin) {
 	pluginInstance = p
 }
 
+// PluginInfo возвращает описание инициализированного плагина.
+func PluginInfo() (info plugin.Info, err error) {
+
+	if pluginInstance == nil {
+		return info, ErrPluginNotSet
+	}
+	return pluginInstance.Info()
+}
+
 // executeHandler обрабатывает запрос на выполнение плагина.
 func executeHandler(req executeRequest) (resp executeResponse, err error) {
 
@@ -42,9 +55,27 @@ func executeHandler(req executeRequest) (resp executeResponse, err error) {
 		return executeResponse{}, fmt.Errorf(i18n.Msg("plugin instance not set"))
 	}
 
-	response, err := pluginInstance.Execute(req.RootDir, requestStorage, req.Path...)
+	// Проверяем настройки по схеме плагина до вызова Execute
+	var info plugin.Info
+	if info, err = pluginInstance.Info(); err != nil {
+		return executeResponse{}, err
+	}
+	if err = plugin.ValidateRequest(info, requestStorage, req.Path...); err != nil {
+		resp.Error, resp.Code, resp.Details = responseError(err)
+		return resp, nil
+	}
+
+	// Контекст выполнения отменяется хостом через экспорт cancel
+	ctx := NewExecuteContext()
+
+	var response data.Storage
+	if executor, ok := pluginInstance.(plugin.ContextExecutor); ok {
+		response, err = executor.ExecuteContext(ctx, req.RootDir, requestStorage, req.Path...)
+	} else {
+		response, err = pluginInstance.Execute(req.RootDir, requestStorage, req.Path...)
+	}
 	if err != nil {
-		resp.Error = err.Error()
+		resp.Error, resp.Code, resp.Details = responseError(err)
 		return resp, nil
 	}
 
@@ -62,5 +93,5 @@ func executeHandler(req executeRequest) (resp executeResponse, err error) {
 //go:wasmexport execute
 func ExecuteExported(ptr uint32, size uint32) (result uint64) {
 
-	return exportWrapper(executeHandler)(ptr, size)
+	return exportWrapper("execute", executeHandler)(ptr, size)
 }
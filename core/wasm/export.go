@@ -9,16 +9,22 @@ import (
 
 	"github.com/goccy/go-json"
 
+	"tgp/core/errs"
 	"tgp/core/i18n"
 )
 
 // exportWrapper создает стандартную обертку для экспорта функции.
 // Автоматически обрабатывает ptr/size запроса, вызывает handler и упаковывает результат в uint64.
 // Handler должен принимать десериализованный запрос и возвращать результат и ошибку.
-func exportWrapper[TRequest any, TResult any](handler func(request TRequest) (result TResult, err error)) func(ptr uint32, size uint32) (result uint64) {
+// panic в handler перехватывается (CrashReport в лог хоста) и возвращается хосту как ошибка с кодом internal.
+func exportWrapper[TRequest any, TResult any](export string, handler func(request TRequest) (result TResult, err error)) func(ptr uint32, size uint32) (result uint64) {
 
 	return func(ptr uint32, size uint32) (result uint64) {
 
+		defer Recover(export, "", func(report CrashReport) {
+			result = createErrorResult(report.Err())
+		})
+
 		// Читаем и десериализуем запрос
 		requestBytes := PtrToByte(ptr, size)
 		Free(ptr)
@@ -26,38 +32,26 @@ func exportWrapper[TRequest any, TResult any](handler func(request TRequest) (re
 		var request TRequest
 		if len(requestBytes) > 0 {
 			if err := json.Unmarshal(requestBytes, &request); err != nil {
-				errorBytes, _ := json.Marshal(map[string]string{
-					"error": fmt.Sprintf(i18n.Msg("failed to unmarshal request")+": %v", err),
-				})
-				return createErrorResultFromBytes(errorBytes)
+				return createErrorResult(errs.Wrap(errs.InvalidArgument, fmt.Errorf(i18n.Msg("failed to unmarshal request")+": %w", err)))
 			}
 		}
 
 		// Вызываем handler
 		response, err := handler(request)
 		if err != nil {
-			errorBytes, _ := json.Marshal(map[string]string{
-				"error": err.Error(),
-			})
-			return createErrorResultFromBytes(errorBytes)
+			return createErrorResult(err)
 		}
 
 		// Сериализуем ответ
 		responseBytes, marshalErr := json.Marshal(response)
 		if marshalErr != nil {
-			errorBytes, _ := json.Marshal(map[string]string{
-				"error": fmt.Sprintf(i18n.Msg("failed to marshal response")+": %v", marshalErr),
-			})
-			return createErrorResultFromBytes(errorBytes)
+			return createErrorResult(errs.Wrap(errs.Internal, fmt.Errorf(i18n.Msg("failed to marshal response")+": %w", marshalErr)))
 		}
 
 		// Выделяем память для результата
 		resultPtr, resultSize := byteToPtr(responseBytes)
 		if resultPtr == 0 {
-			errorBytes, _ := json.Marshal(map[string]string{
-				"error": i18n.Msg("failed to allocate memory for result"),
-			})
-			return createErrorResultFromBytes(errorBytes)
+			return createErrorResult(errs.New(errs.Internal, i18n.Msg("failed to allocate memory for result")))
 		}
 
 		// Возвращаем результат без флага ошибки
@@ -67,38 +61,34 @@ func exportWrapper[TRequest any, TResult any](handler func(request TRequest) (re
 
 // exportWrapperSimple создает обертку для экспорта функции без параметров.
 // Handler должен возвращать только результат и ошибку.
-func exportWrapperSimple[TResult any](handler func() (result TResult, err error)) func(ptr uint32, size uint32) (result uint64) {
+// panic в handler перехватывается так же, как в exportWrapper.
+func exportWrapperSimple[TResult any](export string, handler func() (result TResult, err error)) func(ptr uint32, size uint32) (result uint64) {
 
 	return func(ptr uint32, size uint32) (result uint64) {
 
+		defer Recover(export, "", func(report CrashReport) {
+			result = createErrorResult(report.Err())
+		})
+
 		// Освобождаем память запроса (даже если он пустой)
 		Free(ptr)
 
 		// Вызываем handler
 		response, err := handler()
 		if err != nil {
-			errorBytes, _ := json.Marshal(map[string]string{
-				"error": err.Error(),
-			})
-			return createErrorResultFromBytes(errorBytes)
+			return createErrorResult(err)
 		}
 
 		// Сериализуем ответ
 		responseBytes, marshalErr := json.Marshal(response)
 		if marshalErr != nil {
-			errorBytes, _ := json.Marshal(map[string]string{
-				"error": fmt.Sprintf(i18n.Msg("failed to marshal response")+": %v", marshalErr),
-			})
-			return createErrorResultFromBytes(errorBytes)
+			return createErrorResult(errs.Wrap(errs.Internal, fmt.Errorf(i18n.Msg("failed to marshal response")+": %w", marshalErr)))
 		}
 
 		// Выделяем память для результата
 		resultPtr, resultSize := byteToPtr(responseBytes)
 		if resultPtr == 0 {
-			errorBytes, _ := json.Marshal(map[string]string{
-				"error": i18n.Msg("failed to allocate memory for result"),
-			})
-			return createErrorResultFromBytes(errorBytes)
+			return createErrorResult(errs.New(errs.Internal, i18n.Msg("failed to allocate memory for result")))
 		}
 
 		// Возвращаем результат без флага ошибки
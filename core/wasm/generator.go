@@ -4,12 +4,17 @@
 
 package wasm
 
-var initGeneratorGenerateFunc func(rootDir string, moduleName string) (err error)
+import (
+	"tgp/core/errs"
+)
+
+var initGeneratorGenerateFunc func(rootDir string, moduleName string, options GenerateOptions) (report *GenerateReport, err error)
 var initGeneratorCleanupFunc func(rootDir string) (err error)
 
 // SetInitGeneratorInstance устанавливает экземпляр генератора для plugin init.
+// generateFunc возвращает report только в режимах DryRun и Check.
 func SetInitGeneratorInstance(
-	generateFunc func(rootDir string, moduleName string) (err error),
+	generateFunc func(rootDir string, moduleName string, options GenerateOptions) (report *GenerateReport, err error),
 	cleanupFunc func(rootDir string) (err error),
 ) {
 
@@ -21,11 +26,17 @@ func SetInitGeneratorInstance(
 type generateRequest struct {
 	RootDir    string `json:"rootDir"`
 	ModuleName string `json:"moduleName"`
+	GenerateOptions
 }
 
 // generateResponse представляет ответ на генерацию кода.
+// Files и Summary заполняются в режимах DryRun и Check (в Check - и вместе с ошибкой).
 type generateResponse struct {
-	Error string `json:"error,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Code    errs.Code        `json:"code,omitempty"`
+	Details map[string]any   `json:"details,omitempty"`
+	Files   []GeneratedFile  `json:"files,omitempty"`
+	Summary *GenerateSummary `json:"summary,omitempty"`
 }
 
 // cleanupRequest представляет запрос на очистку сгенерированных файлов.
@@ -35,7 +46,9 @@ type cleanupRequest struct {
 
 // cleanupResponse представляет ответ на очистку.
 type cleanupResponse struct {
-	Error string `json:"error,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Code    errs.Code      `json:"code,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
 }
 
 // generateHandler обрабатывает запрос на генерацию кода.
@@ -45,8 +58,13 @@ func generateHandler(req generateRequest) (resp generateResponse, err error) {
 		return generateResponse{}, nil
 	}
 
-	if err = initGeneratorGenerateFunc(req.RootDir, req.ModuleName); err != nil {
-		resp.Error = err.Error()
+	var report *GenerateReport
+	report, err = initGeneratorGenerateFunc(req.RootDir, req.ModuleName, req.GenerateOptions)
+	if report != nil {
+		resp.Files, resp.Summary = report.Files, &report.Summary
+	}
+	if err != nil {
+		resp.Error, resp.Code, resp.Details = responseError(err)
 		return resp, nil
 	}
 
@@ -61,7 +79,7 @@ func cleanupHandler(req cleanupRequest) (resp cleanupResponse, err error) {
 	}
 
 	if err = initGeneratorCleanupFunc(req.RootDir); err != nil {
-		resp.Error = err.Error()
+		resp.Error, resp.Code, resp.Details = responseError(err)
 		return resp, nil
 	}
 
@@ -73,7 +91,7 @@ func cleanupHandler(req cleanupRequest) (resp cleanupResponse, err error) {
 //go:wasmexport generate
 func GenerateExported(ptr uint32, size uint32) (result uint64) {
 
-	return exportWrapper(generateHandler)(ptr, size)
+	return exportWrapper("generate", generateHandler)(ptr, size)
 }
 
 // CleanupExported обертка для экспорта функции cleanup через WASM.
@@ -81,5 +99,5 @@ func GenerateExported(ptr uint32, size uint32) (result uint64) {
 //go:wasmexport cleanup
 func CleanupExported(ptr uint32, size uint32) (result uint64) {
 
-	return exportWrapper(cleanupHandler)(ptr, size)
+	return exportWrapper("cleanup", cleanupHandler)(ptr, size)
 }
@@ -7,7 +7,7 @@ package wasm
 // SetInitGeneratorInstance устанавливает экземпляр генератора для plugin init.
 // Для не-WASM сборок ничего не делает.
 func SetInitGeneratorInstance(
-	generateFunc func(rootDir string, moduleName string) (err error),
+	generateFunc func(rootDir string, moduleName string, options GenerateOptions) (report *GenerateReport, err error),
 	cleanupFunc func(rootDir string) (err error),
 ) {
 
@@ -40,5 +40,5 @@ func infoHandler() (resp infoResponse, err error) {
 //go:wasmexport info
 func InfoExported(ptr uint32, size uint32) (result uint64) {
 
-	return exportWrapperSimple(infoHandler)(ptr, size)
+	return exportWrapperSimple("info", infoHandler)(ptr, size)
 }
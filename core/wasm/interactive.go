@@ -11,8 +11,11 @@ import (
 )
 
 // InteractiveSelect выполняет интерактивный выбор опции через хост.
-// Заглушка для не-WASM сборок.
+// Для не-WASM сборок делегирует эмулятору хоста, без него возвращает ошибку.
 func InteractiveSelect(prompt string, options []string, multiSelect bool, defaultOptions []string) (selected []string, err error) {
 
+	if emulator := Emulator(); emulator != nil {
+		return emulator.InteractiveSelect(prompt, options, multiSelect, defaultOptions)
+	}
 	return nil, errors.New(i18n.Msg("interactive select is only available in WASM builds"))
 }
@@ -5,7 +5,6 @@
 package wasm
 
 import (
-	"errors"
 	"fmt"
 	"sync"
 	"unsafe"
@@ -161,8 +160,7 @@ func retUint64ToError(u64 uint64) (err error) {
 	// Проверяем флаг ошибки (31-й бит в length)
 	if length&(1<<31) != 0 {
 		length ^= 1 << 31 // Убираем флаг ошибки
-		errMsg := ptrToString(ptr, length)
-		return errors.New(errMsg)
+		return decodeHostError(PtrToByte(ptr, length))
 	}
 	return nil
 }
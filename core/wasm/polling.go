@@ -9,6 +9,7 @@ import (
 )
 
 // AdaptivePollingRead выполняет адаптивный polling с проверкой готовности для чтения.
+// Используется WaitRingBuffer, если буфер закрыт хостом, но checkFunc ещё не вернул результат.
 // checkFunc - функция проверки готовности, возвращает (ready, n, error).
 // deadline - время истечения ожидания (может быть нулевым).
 // Возвращает количество прочитанных/записанных байт и ошибку при истечении deadline или при ошибке checkFunc.
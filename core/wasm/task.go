@@ -7,12 +7,14 @@ package wasm
 import (
 	"encoding/binary"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/goccy/go-json"
 
 	"tgp/core/data"
+	"tgp/core/errs"
 	"tgp/core/i18n"
 )
 
@@ -112,7 +114,7 @@ func startTask(intervalMs uint32, handlerID uint32) (taskID uint32, err error) {
 
 	// Проверяем наличие ошибки
 	if response.Error != "" {
-		return 0, fmt.Errorf(i18n.Msg("task error: %s"), response.Error)
+		return 0, fmt.Errorf(i18n.Msg("task error")+": %w", errs.Decode(response.Error, response.Code, response.Details))
 	}
 
 	// Десериализуем Response в MapStorage
@@ -164,10 +166,7 @@ func StopTask(taskID uint32) (err error) {
 	defer Free(resultPtr)
 
 	// Декодируем JSON ответ
-	var response struct {
-		Error    string          `json:"error,omitempty"`
-		Response json.RawMessage `json:"response,omitempty"`
-	}
+	var response taskResponse
 	if err = json.Unmarshal(resultBytes, &response); err != nil {
 		return fmt.Errorf(i18n.Msg("failed to decode response")+": %w", err)
 	}
@@ -176,7 +175,7 @@ func StopTask(taskID uint32) (err error) {
 	if response.Error != "" {
 		// Удаляем задачу из списка активных даже при ошибке
 		unregisterActiveTask(taskID)
-		return fmt.Errorf(i18n.Msg("task error: %s"), response.Error)
+		return fmt.Errorf(i18n.Msg("task error")+": %w", errs.Decode(response.Error, response.Code, response.Details))
 	}
 
 	// Удаляем задачу из списка активных
@@ -218,17 +217,14 @@ func StopAll() (err error) {
 	defer Free(resultPtr)
 
 	// Декодируем JSON ответ
-	var response struct {
-		Error    string          `json:"error,omitempty"`
-		Response json.RawMessage `json:"response,omitempty"`
-	}
+	var response taskResponse
 	if err = json.Unmarshal(resultBytes, &response); err != nil {
 		return fmt.Errorf(i18n.Msg("failed to decode response")+": %w", err)
 	}
 
 	// Проверяем наличие ошибки
 	if response.Error != "" {
-		return fmt.Errorf(i18n.Msg("task error: %s"), response.Error)
+		return fmt.Errorf(i18n.Msg("task error")+": %w", errs.Decode(response.Error, response.Code, response.Details))
 	}
 
 	// Очищаем список активных задач
@@ -259,6 +255,7 @@ func unregisterActiveTask(taskID uint32) {
 // Сигнатура: (handlerIDPtr uint32, handlerIDSize uint32) -> next uint64
 // Экспортируется для вызова из хоста.
 // next: нижние 32 бита содержат next (1 = true, 0 = false)
+// panic в обработчике перехватывается, а задача останавливается (next = 0).
 //
 //go:wasmexport task_handler
 //nolint:unused // Экспортируется через WASM
@@ -278,6 +275,11 @@ func taskHandler(handlerIDPtr uint32, handlerIDSize uint32) (next uint64) {
 		return 0 // Обработчик не найден - завершаем задачу
 	}
 
+	// Задача, обработчик которой вызвал panic, останавливается; плагин продолжает работу
+	defer Recover("task_handler", strconv.FormatUint(uint64(handlerID), 10), func(report CrashReport) {
+		next = 0
+	})
+
 	// Вызываем обработчик
 	shouldContinue := handler()
 	if shouldContinue {
@@ -16,22 +16,39 @@ import (
 type TaskHandler func() (next bool)
 
 // StartTask запускает фоновую задачу с указанным интервалом.
-// Для не-WASM сборок возвращает ошибку.
+// Для не-WASM сборок делегирует эмулятору хоста, без него возвращает ошибку.
+// Как и в WASM сборке, задача, обработчик которой вызвал panic, останавливается.
 func StartTask(interval time.Duration, handler TaskHandler) (taskID uint32, err error) {
 
+	if emulator := Emulator(); emulator != nil {
+		if handler == nil {
+			return emulator.StartTask(interval, nil)
+		}
+		return emulator.StartTask(interval, func() (next bool) {
+
+			defer Recover("task_handler", "", nil)
+			return handler()
+		})
+	}
 	return 0, errors.New(i18n.Msg("tasks are only available in WASM builds"))
 }
 
 // StopTask останавливает задачу по taskID.
-// Для не-WASM сборок ничего не делает.
+// Для не-WASM сборок делегирует эмулятору хоста, без него ничего не делает.
 func StopTask(taskID uint32) (err error) {
 
+	if emulator := Emulator(); emulator != nil {
+		return emulator.StopTask(taskID)
+	}
 	return nil
 }
 
 // StopAll останавливает все активные задачи.
-// Для не-WASM сборок ничего не делает.
+// Для не-WASM сборок делегирует эмулятору хоста, без него ничего не делает.
 func StopAll() (err error) {
 
+	if emulator := Emulator(); emulator != nil {
+		return emulator.StopAll()
+	}
 	return nil
 }
@@ -1,10 +1,8 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"log/slog"
-	"runtime/debug"
 
 	"tgp/core"
 	"tgp/core/data"
@@ -14,51 +12,62 @@ import (
 )
 
 // DemoPlugin реализует интерфейс Plugin.
-type DemoPlugin struct{}
+type DemoPlugin struct {
+	router *plugin.Router
+}
+
+// ServeOpts описывает настройки команды demo serve.
+type ServeOpts struct {
+	Addr string `tg:"addr,short=a,default=:8080,pattern=^[^ ]*:[0-9]+$" desc:"Address for HTTP server"`
+	TLS  bool   `tg:"tls" desc:"Serve over HTTPS with a self-signed development certificate"`
+}
+
+// newDemoPlugin создаёт плагин и регистрирует его команды.
+func newDemoPlugin() (p *DemoPlugin) {
 
-// Execute выполняет основную логику плагина.
+	p = &DemoPlugin{router: plugin.NewRouter()}
+	plugin.HandleOptions(p.router, plugin.Command{
+		Path:        []string{"demo", "serve"},
+		Description: i18n.Msg("Start interactive demonstration of plugin capabilities"),
+	}, p.serve)
+	return p
+}
+
+// Execute выполняет основную логику плагина без возможности отмены.
 func (p *DemoPlugin) Execute(rootDir string, request data.Storage, path ...string) (response data.Storage, err error) {
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("panic in demo plugin: %v\n%s", r, debug.Stack())
-			slog.Error("panic recovered in demo plugin",
-				slog.Any("panic", r),
-				slog.String("stack", string(debug.Stack())),
-			)
-			if response == nil {
-				response = data.NewStorage()
-			}
-		}
-	}()
+	return p.ExecuteContext(context.Background(), rootDir, request, path...)
+}
+
+// ExecuteContext выполняет команду плагина, выбранную по path.
+func (p *DemoPlugin) ExecuteContext(ctx context.Context, rootDir string, request data.Storage, path ...string) (response data.Storage, err error) {
+
+	return p.router.ExecuteContext(ctx, rootDir, request, path...)
+}
+
+// serve запускает демо сервер (команда demo serve).
+// Сервер продолжает работу после возврата из Execute. Временная директория удаляется в srv.Stop:
+// по запросу /api/demo/stop или при отмене ctx хостом (Ctrl+C или следующий Execute, заменяющий текущий).
+func (p *DemoPlugin) serve(ctx context.Context, rootDir string, request data.Storage, opts ServeOpts) (response data.Storage, err error) {
 
 	slog.Info(i18n.Msg("demo plugin started"))
 
 	response = data.NewStorage()
 
-	addr := ":8080"
-	if request != nil {
-		if addrRaw, ok := request.GetRaw("addr"); ok {
-			var addrVal string
-			if err = json.Unmarshal(addrRaw, &addrVal); err == nil && addrVal != "" {
-				addr = addrVal
-			}
-		}
-	}
-
 	srv := server.NewServer(rootDir, request)
 
-	defer func() {
-		if cleanupErr := server.CleanupTempDir(); cleanupErr != nil {
-			slog.Warn("failed to cleanup temp directory", slog.Any("error", cleanupErr))
-		}
-	}()
-
-	if err = srv.Start(addr); err != nil {
+	if err = srv.Start(opts.Addr, opts.TLS); err != nil {
 		slog.Error("failed to start server", slog.Any("error", err))
+		srv.Stop()
 		return
 	}
 
+	// Останавливаем сервер и удаляем временную директорию по отмене контекста выполнения
+	context.AfterFunc(ctx, func() {
+		slog.Info(i18n.Msg("demo plugin canceled"), slog.Any("cause", context.Cause(ctx)))
+		srv.Stop()
+	})
+
 	return
 }
 
@@ -66,27 +75,12 @@ func (p *DemoPlugin) Execute(rootDir string, request data.Storage, path ...strin
 func (p *DemoPlugin) Info() (info plugin.Info, err error) {
 
 	info = plugin.Info{
-		Name:        "demo",
-		Description: i18n.Msg("Demo plugin"),
-		Author:      "AlexK <seniorGolang@gmail.com>",
-		License:     "MIT",
-		Category:    "utility",
-		Commands: []plugin.Command{
-			{
-				Path:        []string{"demo", "serve"},
-				Description: i18n.Msg("Start interactive demonstration of plugin capabilities"),
-				Options: []plugin.Option{
-					{
-						Name:        "addr",
-						Short:       "a",
-						Type:        "string",
-						Description: i18n.Msg("Address for HTTP server"),
-						Required:    false,
-						Default:     ":8080",
-					},
-				},
-			},
-		},
+		Name:             "demo",
+		Description:      i18n.Msg("Demo plugin"),
+		Author:           "AlexK <seniorGolang@gmail.com>",
+		License:          "MIT",
+		Category:         "utility",
+		Commands:         p.router.Commands(),
 		AllowedHosts:     []string{"localhost", "127.0.0.1", "httpbin.org"},
 		AllowedShellCMDs: []string{"uname", "go", "date"},
 		AllowedEnvVars:   []string{"PATH", "HOME", "USER", "GOROOT", "GOPATH"},
@@ -99,7 +93,7 @@ func (p *DemoPlugin) Info() (info plugin.Info, err error) {
 
 func init() {
 
-	core.InitPlugin(&DemoPlugin{})
+	core.InitPlugin(newDemoPlugin())
 }
 
 func main() {
@@ -2,12 +2,12 @@ package server
 
 import (
 	"fmt"
-	"io"
 	"log/slog"
 	"os"
 	"time"
 
 	"tgp/core/exec"
+	"tgp/core/gotool"
 	"tgp/core/http"
 	"tgp/core/i18n"
 )
@@ -19,88 +19,28 @@ func (s *Server) handleHostInfo(w http.ResponseWriter, r *http.Request) {
 	info := make(map[string]string)
 	result.HostInfo = info
 
-	cmd := exec.Command("uname", "-a")
-	cmd = cmd.Dir(s.rootDir)
-	if err := cmd.Start(); err != nil {
-		slog.Warn("failed to start uname command", slog.Any("error", err))
-	} else {
-		var stdoutPipe io.ReadCloser
-		var err error
-		if stdoutPipe, err = cmd.StdoutPipe(); err != nil {
-			slog.Warn("failed to create stdout pipe for uname", slog.Any("error", err))
-		} else {
-			defer stdoutPipe.Close()
-
-			var stdoutBytes []byte
-			var readErr error
-			if stdoutBytes, readErr = io.ReadAll(stdoutPipe); readErr != nil {
-				slog.Warn("failed to read stdout from uname", slog.Any("error", readErr), slog.Int("bytesRead", len(stdoutBytes)))
-			} else {
-				if waitErr := cmd.Wait(); waitErr != nil {
-					slog.Warn("uname command failed", slog.Any("error", waitErr))
-				} else {
-					output := string(stdoutBytes)
-					info["os"] = output
-					slog.Debug("uname command succeeded", slog.String("output", output), slog.Int("outputLen", len(output)))
-				}
-			}
-		}
+	commands := []struct {
+		key  string
+		name string
+		args []string
+	}{
+		{key: "os", name: "uname", args: []string{"-a"}},
+		{key: "date", name: "date"},
 	}
-
-	cmd2 := exec.Command("go", "version")
-	cmd2 = cmd2.Dir(s.rootDir)
-	if err := cmd2.Start(); err != nil {
-		slog.Warn("failed to start go version command", slog.Any("error", err))
-	} else {
-		var stdoutPipe io.ReadCloser
-		var err error
-		if stdoutPipe, err = cmd2.StdoutPipe(); err != nil {
-			slog.Warn("failed to create stdout pipe for go version", slog.Any("error", err))
-		} else {
-			defer stdoutPipe.Close()
-
-			var stdoutBytes []byte
-			var readErr error
-			if stdoutBytes, readErr = io.ReadAll(stdoutPipe); readErr != nil {
-				slog.Warn("failed to read stdout from go version", slog.Any("error", readErr), slog.Int("bytesRead", len(stdoutBytes)))
-			} else {
-				if waitErr := cmd2.Wait(); waitErr != nil {
-					slog.Warn("go version command failed", slog.Any("error", waitErr))
-				} else {
-					output := string(stdoutBytes)
-					info["goVersion"] = output
-					slog.Debug("go version command succeeded", slog.String("output", output), slog.Int("outputLen", len(output)))
-				}
-			}
+	for _, command := range commands {
+		output, err := exec.Command(command.name, command.args...).Dir(s.rootDir).Output()
+		if err != nil {
+			slog.Warn("host info command failed", slog.String("command", command.name), slog.Any("error", err))
+			continue
 		}
+		info[command.key] = string(output)
+		slog.Debug("host info command succeeded", slog.String("command", command.name), slog.Int("outputLen", len(output)))
 	}
 
-	cmd3 := exec.Command("date")
-	cmd3 = cmd3.Dir(s.rootDir)
-	if err := cmd3.Start(); err != nil {
-		slog.Warn("failed to start date command", slog.Any("error", err))
+	if version, err := gotool.Version(r.Context()); err != nil {
+		slog.Warn("host info command failed", slog.String("command", "go"), slog.Any("error", err))
 	} else {
-		var stdoutPipe io.ReadCloser
-		var err error
-		if stdoutPipe, err = cmd3.StdoutPipe(); err != nil {
-			slog.Warn("failed to create stdout pipe for date", slog.Any("error", err))
-		} else {
-			defer stdoutPipe.Close()
-
-			var stdoutBytes []byte
-			var readErr error
-			if stdoutBytes, readErr = io.ReadAll(stdoutPipe); readErr != nil {
-				slog.Warn("failed to read stdout from date", slog.Any("error", readErr), slog.Int("bytesRead", len(stdoutBytes)))
-			} else {
-				if waitErr := cmd3.Wait(); waitErr != nil {
-					slog.Warn("date command failed", slog.Any("error", waitErr))
-				} else {
-					output := string(stdoutBytes)
-					info["date"] = output
-					slog.Debug("date command succeeded", slog.String("output", output), slog.Int("outputLen", len(output)))
-				}
-			}
-		}
+		info["goVersion"] = version.String()
 	}
 
 	result.Timestamp = time.Now().Format(time.RFC3339)
@@ -5,6 +5,7 @@ import (
 
 	"tgp/core/data"
 	"tgp/core/http"
+	"tgp/core/net"
 )
 
 // NewServer создает новый сервер демо.
@@ -19,7 +20,8 @@ func NewServer(rootDir string, request data.Storage) (s *Server) {
 }
 
 // Start запускает HTTP сервер на указанном адресе.
-func (s *Server) Start(addr string) (err error) {
+// Если useTLS задан, сервер работает по HTTPS с самоподписанным сертификатом для разработки.
+func (s *Server) Start(addr string, useTLS bool) (err error) {
 
 	mux := http.NewServeMux()
 
@@ -31,6 +33,11 @@ func (s *Server) Start(addr string) (err error) {
 	mux.HandleFunc("/api/demo/http-client", s.handleHTTPClient)
 	mux.HandleFunc("/api/demo/server/status", s.handleServerStatus)
 	mux.HandleFunc("/api/demo/command", s.handleCommand)
+	mux.HandleFunc("/api/demo/terminal/start", s.handleTerminalStart)
+	mux.HandleFunc("/api/demo/terminal/output", s.handleTerminalOutput)
+	mux.HandleFunc("/api/demo/terminal/input", s.handleTerminalInput)
+	mux.HandleFunc("/api/demo/terminal/resize", s.handleTerminalResize)
+	mux.HandleFunc("/api/demo/terminal/close", s.handleTerminalClose)
 	mux.HandleFunc("/api/demo/plan", s.handlePlan)
 	mux.HandleFunc("/api/demo/host-info", s.handleHostInfo)
 	mux.HandleFunc("/api/demo/env", s.handleEnv)
@@ -47,10 +54,20 @@ func (s *Server) Start(addr string) (err error) {
 	mux.HandleFunc("/api/test", s.handleTest)
 	mux.HandleFunc("/api/echo", s.handleEcho)
 
-	slog.Info("starting demo server", slog.String("addr", addr))
+	slog.Info("starting demo server", slog.String("addr", addr), slog.Bool("tls", useTLS))
 
 	var serverID uint64
-	if serverID, err = http.ListenAndServe(addr, mux); err != nil {
+	if useTLS {
+		var config net.ServerTLSConfig
+		if config, err = net.SelfSignedTLSConfig(); err != nil {
+			slog.Error("failed to generate development certificate", slog.Any("error", err))
+			return
+		}
+		serverID, err = http.ListenAndServeTLSConfig(addr, config, mux)
+	} else {
+		serverID, err = http.ListenAndServe(addr, mux)
+	}
+	if err != nil {
 		slog.Error("failed to start demo server", slog.String("addr", addr), slog.Any("error", err))
 		return
 	}
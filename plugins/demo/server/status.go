@@ -55,6 +55,16 @@ func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
 	}
 	s.writeHTML(w, http.StatusOK, formatResult(result))
 
+	s.Stop()
+}
+
+// Stop останавливает HTTP сервер и удаляет временную директорию демо.
+// Вызывается из /api/demo/stop, при отмене выполнения плагина хостом и при ошибке запуска.
+func (s *Server) Stop() {
+
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+
 	if s.serverID != 0 {
 		if stopErr := http.StopServerByID(s.serverID); stopErr != nil {
 			slog.Error("failed to stop server", slog.Uint64("serverID", s.serverID), slog.Any("error", stopErr))
@@ -63,9 +73,11 @@ func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
 			s.serverID = 0
 		}
 	} else {
-		slog.Info("serverID not set, server will stop automatically when Execute completes")
+		slog.Info("serverID not set, server is not running")
 	}
 
+	s.closeTerminal()
+
 	if cleanupErr := CleanupTempDir(); cleanupErr != nil {
 		slog.Warn("failed to cleanup temp directory after stop", slog.Any("error", cleanupErr))
 	}
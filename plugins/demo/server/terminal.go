@@ -0,0 +1,324 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"tgp/core/exec"
+	"tgp/core/http"
+	"tgp/core/i18n"
+)
+
+// terminalOutputLimit ограничивает объём вывода терминала, хранимого для отображения.
+const terminalOutputLimit = 64 * 1024
+
+// terminalTokenHeader - заголовок с токеном сессии терминала.
+const terminalTokenHeader = "X-Terminal-Token"
+
+// terminalCommands - фиксированный набор команд, которые можно запустить в терминале.
+// Произвольные команды (и shell) не запускаются: все они перечислены в AllowedShellCMDs плагина.
+var terminalCommands = map[string][]string{
+	"date":   {"date"},
+	"uname":  {"uname", "-a"},
+	"go-env": {"go", "env"},
+}
+
+// terminalSession представляет команду, запущенную в псевдотерминале.
+type terminalSession struct {
+	token    string
+	cmd      *exec.Cmd
+	term     *exec.Terminal
+	mu       sync.Mutex
+	output   []byte
+	done     bool
+	exitCode int
+}
+
+// read копирует вывод терминала в буфер сессии до завершения команды.
+func (t *terminalSession) read() {
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := t.term.Read(buf)
+		if n > 0 {
+			t.mu.Lock()
+			t.output = append(t.output, buf[:n]...)
+			if extra := len(t.output) - terminalOutputLimit; extra > 0 {
+				t.output = t.output[extra:]
+			}
+			t.mu.Unlock()
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				slog.Debug("terminal read finished", slog.Any("error", err))
+			}
+			break
+		}
+	}
+
+	_ = t.cmd.Wait()
+	t.mu.Lock()
+	t.done = true
+	t.exitCode = t.cmd.ExitCode()
+	t.mu.Unlock()
+	_ = t.term.Close()
+}
+
+// handleTerminalStart запускает команду из terminalCommands в псевдотерминале (или возвращает уже запущенную).
+func (s *Server) handleTerminalStart(w http.ResponseWriter, r *http.Request) {
+
+	if !s.terminalRequestAllowed(w, r, false) {
+		return
+	}
+	values, ok := s.terminalForm(w, r)
+	if !ok {
+		return
+	}
+
+	s.terminalMu.Lock()
+	defer s.terminalMu.Unlock()
+
+	if s.terminal != nil && !s.terminal.isDone() {
+		s.writeHTML(w, http.StatusOK, formatTerminal(s.terminal.token))
+		return
+	}
+
+	command, found := terminalCommands[values.Get("command")]
+	if !found {
+		s.writeError(w, http.StatusOK, i18n.Msg("unknown terminal command"))
+		return
+	}
+
+	token, err := newTerminalToken()
+	if err != nil {
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to start command"), err))
+		return
+	}
+
+	cmd := exec.Command(command[0], command[1:]...).Dir(s.rootDir).UsePTY(24, 80)
+	if err = cmd.Start(); err != nil {
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to start command"), err))
+		return
+	}
+
+	term, err := cmd.Terminal()
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to open terminal"), err))
+		return
+	}
+
+	s.terminal = &terminalSession{token: token, cmd: cmd, term: term}
+	go s.terminal.read()
+
+	s.writeHTML(w, http.StatusOK, formatTerminal(token))
+}
+
+// handleTerminalOutput возвращает накопленный вывод терминала.
+func (s *Server) handleTerminalOutput(w http.ResponseWriter, r *http.Request) {
+
+	if !s.terminalRequestAllowed(w, r, true) {
+		return
+	}
+
+	session := s.currentTerminal()
+	if session == nil {
+		s.writeHTML(w, http.StatusOK, html.EscapeString(i18n.Msg("Terminal is not started")))
+		return
+	}
+
+	session.mu.Lock()
+	output := html.EscapeString(string(session.output))
+	if session.done {
+		output += "\n" + html.EscapeString(fmt.Sprintf(i18n.Msg("[process exited with code %d]"), session.exitCode))
+	}
+	session.mu.Unlock()
+
+	s.writeHTML(w, http.StatusOK, output)
+}
+
+// handleTerminalInput передаёт строку ввода в терминал.
+func (s *Server) handleTerminalInput(w http.ResponseWriter, r *http.Request) {
+
+	if !s.terminalRequestAllowed(w, r, true) {
+		return
+	}
+	values, ok := s.terminalForm(w, r)
+	if !ok {
+		return
+	}
+
+	session := s.currentTerminal()
+	if session == nil || session.isDone() {
+		s.writeError(w, http.StatusOK, i18n.Msg("Terminal is not started"))
+		return
+	}
+
+	if _, err := session.term.Write([]byte(values.Get("input") + "\n")); err != nil {
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to write to terminal"), err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTerminalResize изменяет размер терминала.
+func (s *Server) handleTerminalResize(w http.ResponseWriter, r *http.Request) {
+
+	if !s.terminalRequestAllowed(w, r, true) {
+		return
+	}
+	values, ok := s.terminalForm(w, r)
+	if !ok {
+		return
+	}
+
+	session := s.currentTerminal()
+	if session == nil || session.isDone() {
+		s.writeError(w, http.StatusOK, i18n.Msg("Terminal is not started"))
+		return
+	}
+
+	rows, rowsErr := strconv.ParseUint(values.Get("rows"), 10, 16)
+	cols, colsErr := strconv.ParseUint(values.Get("cols"), 10, 16)
+	if rowsErr != nil || colsErr != nil || rows == 0 || cols == 0 {
+		s.writeError(w, http.StatusOK, i18n.Msg("rows and cols must be positive numbers"))
+		return
+	}
+
+	if err := session.term.Resize(uint16(rows), uint16(cols)); err != nil {
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to resize terminal"), err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTerminalClose завершает команду терминала.
+func (s *Server) handleTerminalClose(w http.ResponseWriter, r *http.Request) {
+
+	if !s.terminalRequestAllowed(w, r, true) {
+		return
+	}
+	s.closeTerminal()
+	s.writeHTML(w, http.StatusOK, html.EscapeString(i18n.Msg("Terminal closed")))
+}
+
+// closeTerminal завершает команду терминала, если она запущена.
+func (s *Server) closeTerminal() {
+
+	if session := s.currentTerminal(); session != nil && !session.isDone() {
+		if err := session.cmd.Process.Kill(); err != nil {
+			slog.Warn("failed to kill terminal command", slog.Any("error", err))
+		}
+	}
+}
+
+// currentTerminal возвращает текущую сессию терминала или nil.
+func (s *Server) currentTerminal() (session *terminalSession) {
+
+	s.terminalMu.Lock()
+	defer s.terminalMu.Unlock()
+
+	return s.terminal
+}
+
+// terminalRequestAllowed проверяет запрос к терминалу: только POST с loopback адреса,
+// выполненный htmx со страницы демо (Origin совпадает с Host), и с токеном сессии, если requireToken.
+// Заголовки HX-Request и X-Terminal-Token нельзя отправить кросс-сайтовой формой без CORS, которого сервер не разрешает.
+func (s *Server) terminalRequestAllowed(w http.ResponseWriter, r *http.Request, requireToken bool) (allowed bool) {
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip == nil || !ip.IsLoopback() {
+		s.writeError(w, http.StatusForbidden, i18n.Msg("terminal is available only from localhost"))
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if r.Header.Get("HX-Request") != "true" || (origin != "" && origin != "http://"+r.Host && origin != "https://"+r.Host) {
+		s.writeError(w, http.StatusForbidden, i18n.Msg("cross-origin terminal request rejected"))
+		return false
+	}
+
+	if requireToken {
+		session := s.currentTerminal()
+		token := r.Header.Get(terminalTokenHeader)
+		if session == nil || subtle.ConstantTimeCompare([]byte(token), []byte(session.token)) != 1 {
+			s.writeError(w, http.StatusForbidden, i18n.Msg("invalid terminal session token"))
+			return false
+		}
+	}
+	return true
+}
+
+// newTerminalToken создаёт случайный токен сессии терминала.
+func newTerminalToken() (token string, err error) {
+
+	buf := make([]byte, 16)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// terminalForm разбирает тело запроса формы терминала.
+func (s *Server) terminalForm(w http.ResponseWriter, r *http.Request) (values url.Values, ok bool) {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to read body"), err))
+		return nil, false
+	}
+
+	if values, err = url.ParseQuery(string(body)); err != nil {
+		s.writeError(w, http.StatusOK, fmt.Sprintf("%s: %v", i18n.Msg("failed to parse form"), err))
+		return nil, false
+	}
+	return values, true
+}
+
+// isDone сообщает, завершилась ли команда.
+func (t *terminalSession) isDone() (done bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.done
+}
+
+// formatTerminal возвращает HTML терминала: вывод обновляется опросом, ввод отправляется построчно.
+// Все запросы терминала передают токен сессии в заголовке X-Terminal-Token (hx-headers наследуется).
+func formatTerminal(token string) (html string) {
+
+	return `<div hx-headers='{"` + terminalTokenHeader + `": "` + token + `"}'>
+<pre id="terminal-output" class="terminal" hx-post="/api/demo/terminal/output" hx-trigger="load, every 500ms" hx-swap="innerHTML"></pre>
+<form hx-post="/api/demo/terminal/input" hx-swap="none" hx-on::after-request="this.reset()">
+    <input type="text" name="input" autocomplete="off" placeholder="` + i18n.Msg("Input for command") + `">
+    <button type="submit">` + i18n.Msg("Send") + `</button>
+</form>
+<form hx-post="/api/demo/terminal/resize" hx-swap="none">
+    <input type="number" name="rows" value="24" min="1"> x <input type="number" name="cols" value="80" min="1">
+    <button type="submit">` + i18n.Msg("Resize") + `</button>
+</form>
+<button class="danger" hx-post="/api/demo/terminal/close" hx-target="#terminal-output" hx-swap="innerHTML">` + i18n.Msg("Close terminal") + `</button>
+</div>`
+}
@@ -30,8 +30,12 @@ type Server struct {
 	rootDir  string
 	request  data.Storage
 	serverID uint64
+	stopMu   sync.Mutex
 	tasks    map[uint32]*TaskState
 	tasksMu  sync.RWMutex
+	// terminal - shell, запущенный в псевдотерминале (см. terminal.go)
+	terminal   *terminalSession
+	terminalMu sync.Mutex
 }
 
 // TaskState представляет состояние задачи.